@@ -0,0 +1,224 @@
+//go:build go1.18
+// +build go1.18
+
+package jsonpointer
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/dustin/gojson"
+)
+
+// jsonNumber is satisfied by json.Number from both this package's
+// JSON implementation and encoding/json.
+type jsonNumber interface {
+	Int64() (int64, error)
+	Float64() (float64, error)
+	String() string
+}
+
+// GetAs gets the value at the specified path as a T.
+//
+// Numbers decoded as float64 or json.Number may be requested as any
+// integer or float type provided they fit without loss.
+func GetAs[T any](m interface{}, path string) (T, error) {
+	v, ok := GetOK(m, path)
+	if !ok {
+		var zero T
+		return zero, &PointerError{path, ErrNotFound}
+	}
+	return convertAs[T](path, v)
+}
+
+// ReflectAs gets the value at the specified path from a struct as a
+// T, with the same numeric conversions as GetAs.
+func ReflectAs[T any](o interface{}, path string) (T, error) {
+	val, err := ReflectValue(o, path)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if !val.IsValid() {
+		return convertAs[T](path, nil)
+	}
+	if !val.CanInterface() {
+		var zero T
+		return zero, &PointerError{path, ErrNotFound}
+	}
+	return convertAs[T](path, val.Interface())
+}
+
+// FindAs finds the raw JSON at the specified path and decodes it into
+// a T.
+func FindAs[T any](data []byte, path string) (T, error) {
+	var rv T
+	d, err := Find(data, path)
+	if err != nil {
+		return rv, err
+	}
+	if d == nil {
+		return rv, &PointerError{path, ErrNotFound}
+	}
+	if err := json.Unmarshal(d, &rv); err != nil {
+		return rv, &PointerError{path, err}
+	}
+	return rv, nil
+}
+
+func convertAs[T any](path string, v interface{}) (T, error) {
+	if t, ok := v.(T); ok {
+		return t, nil
+	}
+	var rv T
+	if v == nil {
+		// null is only acceptable as the zero value of a type that
+		// can be nil.
+		switch reflect.TypeOf(&rv).Elem().Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			return rv, nil
+		}
+	}
+	if err := convertNumber(reflect.ValueOf(&rv).Elem(), v); err != nil {
+		return rv, &PointerError{path, err}
+	}
+	return rv, nil
+}
+
+// convertNumber stores the number v in dst, failing if v isn't a
+// number or can't be represented exactly by dst's type.
+func convertNumber(dst reflect.Value, v interface{}) error {
+	mismatch := fmt.Errorf("cannot use %T as %v", v, dst.Type())
+	src := reflect.ValueOf(v)
+	if _, ok := v.(jsonNumber); !ok && !isNumberKind(src.Kind()) {
+		return mismatch
+	}
+
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt64(src)
+		if err != nil {
+			return err
+		}
+		if dst.OverflowInt(i) {
+			return fmt.Errorf("%v overflows %v", v, dst.Type())
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := toUint64(src)
+		if err != nil {
+			return err
+		}
+		if dst.OverflowUint(u) {
+			return fmt.Errorf("%v overflows %v", v, dst.Type())
+		}
+		dst.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat64(src)
+		if err != nil {
+			return err
+		}
+		if dst.OverflowFloat(f) {
+			return fmt.Errorf("%v overflows %v", v, dst.Type())
+		}
+		dst.SetFloat(f)
+	default:
+		return mismatch
+	}
+	return nil
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func toInt64(src reflect.Value) (int64, error) {
+	if n, ok := src.Interface().(jsonNumber); ok {
+		if i, err := n.Int64(); err == nil {
+			return i, nil
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %v", n)
+		}
+		return floatToInt64(f)
+	}
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return src.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if src.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%v overflows int64", src.Uint())
+		}
+		return int64(src.Uint()), nil
+	}
+	return floatToInt64(src.Float())
+}
+
+func floatToInt64(f float64) (int64, error) {
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("%v is not an integer", f)
+	}
+	if f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, fmt.Errorf("%v overflows int64", f)
+	}
+	return int64(f), nil
+}
+
+func toUint64(src reflect.Value) (uint64, error) {
+	if n, ok := src.Interface().(jsonNumber); ok {
+		if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+			return u, nil
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %v", n)
+		}
+		return floatToUint64(f)
+	}
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if src.Int() < 0 {
+			return 0, fmt.Errorf("%v overflows uint64", src.Int())
+		}
+		return uint64(src.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return src.Uint(), nil
+	}
+	return floatToUint64(src.Float())
+}
+
+func floatToUint64(f float64) (uint64, error) {
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("%v is not an integer", f)
+	}
+	if f < 0 || f >= math.MaxUint64 {
+		return 0, fmt.Errorf("%v overflows uint64", f)
+	}
+	return uint64(f), nil
+}
+
+func toFloat64(src reflect.Value) (float64, error) {
+	if n, ok := src.Interface().(jsonNumber); ok {
+		f, err := n.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %v", n)
+		}
+		return f, nil
+	}
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(src.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(src.Uint()), nil
+	}
+	return src.Float(), nil
+}
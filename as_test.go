@@ -0,0 +1,153 @@
+//go:build go1.18
+// +build go1.18
+
+package jsonpointer
+
+import (
+	stdjson "encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetAs(t *testing.T) {
+	s, err := GetAs[string](obj, "/foo/0")
+	if err != nil || s != "bar" {
+		t.Errorf("Expected bar, got %q/%v", s, err)
+	}
+
+	i, err := GetAs[int](obj, "/m~0n")
+	if err != nil || i != 8 {
+		t.Errorf("Expected 8, got %v/%v", i, err)
+	}
+
+	u, err := GetAs[uint8](obj, "/g|h")
+	if err != nil || u != 4 {
+		t.Errorf("Expected 4, got %v/%v", u, err)
+	}
+
+	a, err := GetAs[[]interface{}](obj, "/foo")
+	if err != nil || len(a) != 2 {
+		t.Errorf("Expected the foo array, got %v/%v", a, err)
+	}
+
+	_, err = GetAs[int](obj, "/missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+
+	_, err = GetAs[int](obj, "/foo/0")
+	if err == nil || !strings.Contains(err.Error(), `"/foo/0"`) {
+		t.Errorf("Expected a type mismatch naming the pointer, got %v", err)
+	}
+}
+
+func TestGetAsNumbers(t *testing.T) {
+	m := map[string]interface{}{
+		"big":     300.0,
+		"neg":     -1.0,
+		"frac":    1.5,
+		"num":     stdjson.Number("42"),
+		"stdnum":  stdjson.Number("-7"),
+		"hugenum": stdjson.Number("18446744073709551615"),
+		"fracnum": stdjson.Number("2.5"),
+	}
+
+	if v, err := GetAs[int16](m, "/big"); err != nil || v != 300 {
+		t.Errorf("Expected 300, got %v/%v", v, err)
+	}
+	if v, err := GetAs[int8](m, "/big"); err == nil {
+		t.Errorf("Expected overflow, got %v", v)
+	}
+	if v, err := GetAs[uint](m, "/neg"); err == nil {
+		t.Errorf("Expected overflow on negative, got %v", v)
+	}
+	if v, err := GetAs[int](m, "/frac"); err == nil {
+		t.Errorf("Expected error on fraction, got %v", v)
+	}
+	if v, err := GetAs[float32](m, "/frac"); err != nil || v != 1.5 {
+		t.Errorf("Expected 1.5, got %v/%v", v, err)
+	}
+	if v, err := GetAs[int64](m, "/num"); err != nil || v != 42 {
+		t.Errorf("Expected 42, got %v/%v", v, err)
+	}
+	if v, err := GetAs[int](m, "/stdnum"); err != nil || v != -7 {
+		t.Errorf("Expected -7, got %v/%v", v, err)
+	}
+	if v, err := GetAs[uint64](m, "/hugenum"); err != nil || v != 18446744073709551615 {
+		t.Errorf("Expected max uint64, got %v/%v", v, err)
+	}
+	if v, err := GetAs[int64](m, "/hugenum"); err == nil {
+		t.Errorf("Expected overflow, got %v", v)
+	}
+	if v, err := GetAs[float64](m, "/fracnum"); err != nil || v != 2.5 {
+		t.Errorf("Expected 2.5, got %v/%v", v, err)
+	}
+	if v, err := GetAs[stdjson.Number](m, "/num"); err != nil || v != "42" {
+		t.Errorf("Expected the number itself, got %v/%v", v, err)
+	}
+}
+
+func TestReflectAs(t *testing.T) {
+	s, err := ReflectAs[string](input, "/addresses/0/street")
+	if err != nil || s != "123 Sesame St." {
+		t.Errorf("Expected street, got %q/%v", s, err)
+	}
+
+	i, err := ReflectAs[int64](input, "/AnActualArray/3")
+	if err != nil || i != 3 {
+		t.Errorf("Expected 3, got %v/%v", i, err)
+	}
+
+	f, err := ReflectAs[float64](input, "/nestedmap/pi")
+	if err != nil || f != 3.14 {
+		t.Errorf("Expected 3.14, got %v/%v", f, err)
+	}
+
+	_, err = ReflectAs[int](input, "/nestedmap/pi")
+	if err == nil {
+		t.Errorf("Expected error converting 3.14 to int")
+	}
+
+	_, err = ReflectAs[string](input, "/nope")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+}
+
+func TestFindAs(t *testing.T) {
+	s, err := FindAs[string]([]byte(objSrc), "/g/n/r")
+	if err != nil || s != "where's tito?" {
+		t.Errorf("Expected tito, got %q/%v", s, err)
+	}
+
+	i, err := FindAs[uint16]([]byte(objSrc), "/m~0n")
+	if err != nil || i != 8 {
+		t.Errorf("Expected 8, got %v/%v", i, err)
+	}
+
+	_, err = FindAs[int]([]byte(objSrc), "/missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+
+	_, err = FindAs[int]([]byte(objSrc), "/foo")
+	var pe *PointerError
+	if !errors.As(err, &pe) || pe.Pointer != "/foo" {
+		t.Errorf("Expected a pointer error at /foo, got %v", err)
+	}
+}
+
+func TestGetAsNull(t *testing.T) {
+	m := map[string]interface{}{"n": nil}
+
+	if v, err := GetAs[interface{}](m, "/n"); err != nil || v != nil {
+		t.Errorf("Expected nil interface, got %v/%v", v, err)
+	}
+	if v, err := GetAs[map[string]interface{}](m, "/n"); err != nil || v != nil {
+		t.Errorf("Expected nil map, got %v/%v", v, err)
+	}
+	if v, err := GetAs[string](m, "/n"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a type mismatch on null, got %q/%v", v, err)
+	}
+}
@@ -1,102 +1,15 @@
 package jsonpointer
 
 import (
+	"context"
 	"fmt"
-	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/dustin/gojson"
 )
 
-func arreq(a, b []string) bool {
-	if len(a) == len(b) {
-		for i := range a {
-			if a[i] != b[i] {
-				return false
-			}
-		}
-		return true
-	}
-
-	return false
-}
-
-func unescape(s string) string {
-	n := strings.Count(s, "~")
-	if n == 0 {
-		return s
-	}
-
-	t := make([]byte, len(s)-n+1) // remove one char per ~
-	w := 0
-	start := 0
-	for i := 0; i < n; i++ {
-		j := start + strings.Index(s[start:], "~")
-		w += copy(t[w:], s[start:j])
-		if len(s) < j+2 {
-			t[w] = '~'
-			w++
-			break
-		}
-		c := s[j+1]
-		switch c {
-		case '0':
-			t[w] = '~'
-			w++
-		case '1':
-			t[w] = '/'
-			w++
-		default:
-			t[w] = '~'
-			w++
-			t[w] = c
-			w++
-		}
-		start = j + 2
-	}
-	w += copy(t[w:], s[start:])
-	return string(t[0:w])
-}
-
-func parsePointer(s string) []string {
-	a := strings.Split(s[1:], "/")
-	if !strings.Contains(s, "~") {
-		return a
-	}
-
-	for i := range a {
-		if strings.Contains(a[i], "~") {
-			a[i] = unescape(a[i])
-		}
-	}
-	return a
-}
-
-func escape(s string, out []rune) []rune {
-	for _, c := range s {
-		switch c {
-		case '/':
-			out = append(out, '~', '1')
-		case '~':
-			out = append(out, '~', '0')
-		default:
-			out = append(out, c)
-		}
-	}
-	return out
-}
-
-func encodePointer(p []string) string {
-	out := make([]rune, 0, 64)
-
-	for _, s := range p {
-		out = append(out, '/')
-		out = escape(s, out)
-	}
-	return string(out)
-}
-
 func grokLiteral(b []byte) string {
 	s, ok := json.UnquoteBytes(b)
 	if !ok {
@@ -120,63 +33,30 @@ func FindDecode(data []byte, path string, into interface{}) error {
 	return json.Unmarshal(d, into)
 }
 
+// Exists reports whether a value (possibly null) exists at the given
+// JSONPointer path.
+func Exists(data []byte, path string) (bool, error) {
+	d, err := Find(data, path)
+	return d != nil, err
+}
+
 // Find a section of raw JSON by specifying a JSONPointer.
+//
+// Of several object members with the same key, only the first is
+// searched, as DuplicateFirst describes; FindWith offers the other
+// policies.
+//
+// Invalid JSON is reported as a *SyntaxError.  Once the value is
+// found, the rest of the document is only checked for balanced
+// brackets and for garbage after the top-level value, so Find may
+// succeed on documents that are otherwise malformed away from the
+// path.
 func Find(data []byte, path string) ([]byte, error) {
 	if path == "" {
 		return data, nil
 	}
 
-	needle := parsePointer(path)
-
-	scan := &json.Scanner{}
-	scan.Reset()
-
-	offset := 0
-	beganLiteral := 0
-	current := make([]string, 0, 32)
-	for {
-		if offset >= len(data) {
-			break
-		}
-		newOp := scan.Step(scan, int(data[offset]))
-		offset++
-
-		switch newOp {
-		case json.ScanBeginArray:
-			current = append(current, "0")
-		case json.ScanObjectKey:
-			current[len(current)-1] = grokLiteral(data[beganLiteral-1 : offset-1])
-		case json.ScanBeginLiteral:
-			beganLiteral = offset
-		case json.ScanArrayValue:
-			n := mustParseInt(current[len(current)-1])
-			current[len(current)-1] = strconv.Itoa(n + 1)
-		case json.ScanEndArray, json.ScanEndObject:
-			current = sliceToEnd(current)
-		case json.ScanBeginObject:
-			current = append(current, "")
-		case json.ScanContinue, json.ScanSkipSpace, json.ScanObjectValue, json.ScanEnd:
-		default:
-			return nil, fmt.Errorf("found unhandled json op: %v", newOp)
-		}
-
-		if (newOp == json.ScanBeginArray || newOp == json.ScanArrayValue ||
-			newOp == json.ScanObjectKey) && arreq(needle, current) {
-			otmp := offset
-			for isSpace(rune(data[otmp])) {
-				otmp++
-			}
-			if data[otmp] == ']' {
-				// special case an array offset miss
-				offset = otmp
-				return nil, nil
-			}
-			val, _, err := json.NextValue(data[offset:], scan)
-			return val, err
-		}
-	}
-
-	return nil, nil
+	return FindWith(data, path, FindOptions{})
 }
 
 func sliceToEnd(s []string) []string {
@@ -196,133 +76,540 @@ func mustParseInt(s string) int {
 	panic(err)
 }
 
-// ListPointers lists all possible pointers from the given input.
+// ListPointers lists all possible pointers from the given input.  For
+// compatibility, each empty array is listed with an element 0, which
+// ListPointersWith leaves out.
 func ListPointers(data []byte) ([]string, error) {
+	opts := ListOptions{}
+	return listPointers(context.Background(), data, &opts, true)
+}
+
+// ListOptions restricts which pointers are listed.
+type ListOptions struct {
+	// LeavesOnly lists only pointers to scalar values.
+	LeavesOnly bool
+	// ContainersOnly lists only pointers to objects and arrays.
+	ContainersOnly bool
+	// MaxDepth, when positive, omits pointers with more tokens.
+	MaxDepth int
+	// Prefix lists only the subtree at the given pointer.
+	Prefix string
+	// Duplicates decides which members with the same key are
+	// listed.  Pointers are never listed twice.
+	Duplicates DuplicatePolicy
+	// Limits bounds the input read and the pointers listed.
+	Limits Limits
+}
+
+// byKind reports whether listing depends on the kind of each value.
+func (o *ListOptions) byKind() bool {
+	return o.LeavesOnly || o.ContainersOnly
+}
+
+// wants reports whether to list a pointer with the given number of
+// tokens to a container or scalar.
+func (o *ListOptions) wants(depth int, container bool) bool {
+	if o.MaxDepth > 0 && depth > o.MaxDepth {
+		return false
+	}
+	if o.LeavesOnly && container {
+		return false
+	}
+	return container || !o.ContainersOnly
+}
+
+// ListPointersWith lists the pointers from the given input selected by
+// the options.
+func ListPointersWith(data []byte, opts ListOptions) ([]string, error) {
+	return ListPointersContext(context.Background(), data, opts)
+}
+
+// ListPointersContext is ListPointersWith, returning ctx's error if
+// ctx is done before the scan completes.
+func ListPointersContext(ctx context.Context, data []byte, opts ListOptions) ([]string, error) {
+	return listPointers(ctx, data, &opts, false)
+}
+
+// listPointers lists the pointers selected by opts, and an element 0
+// within each empty array if emptyIndex is set.
+func listPointers(ctx context.Context, data []byte, opts *ListOptions,
+	emptyIndex bool) ([]string, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if len(data) == 0 {
 		return nil, fmt.Errorf("Invalid JSON")
 	}
-	rv := []string{""}
+	lim := &opts.Limits
+	if err := lim.checkSize(data); err != nil {
+		return nil, err
+	}
+	// base is the depth of the listing, and start the offset of its
+	// input, within the document.
+	base, start := 0, 0
+	if opts.Prefix != "" {
+		sub, err := FindContext(ctx, data, opts.Prefix, FindOptions{opts.Duplicates, opts.Limits})
+		if err != nil || sub == nil {
+			return nil, err
+		}
+		// sub is a slice of data.
+		start = cap(data) - cap(sub)
+		data = sub
+		base = len(SplitLenient(opts.Prefix))
+	}
 
-	scan := &json.Scanner{}
-	scan.Reset()
+	var rv []string
+	// When listing by kind, pending is set until the value of the
+	// most recent pointer begins, and at is that pointer's depth.
+	pending := opts.byKind()
+	at := base
+	if !pending && opts.wants(base, false) {
+		rv = append(rv, opts.Prefix)
+	}
 
-	offset := 0
-	beganLiteral := 0
-	var current []string
-	for {
-		if offset >= len(data) {
-			return rv, nil
+	w := newWalker(data)
+	w.prefix, w.base, w.start = opts.Prefix, base, start
+	w.lim = lim
+	w.cancel.ctx = ctx
+	w.trackDuplicates(opts.Duplicates)
+	for !w.done() {
+		newOp, err := w.step()
+		if err != nil {
+			return nil, err
 		}
-		newOp := scan.Step(scan, int(data[offset]))
-		offset++
 
-		switch newOp {
-		case json.ScanBeginArray:
-			current = append(current, "0")
-		case json.ScanObjectKey:
-			current[len(current)-1] = grokLiteral(data[beganLiteral-1 : offset-1])
-		case json.ScanBeginLiteral:
-			beganLiteral = offset
-		case json.ScanArrayValue:
-			n := mustParseInt(current[len(current)-1])
-			current[len(current)-1] = strconv.Itoa(n + 1)
-		case json.ScanEndArray, json.ScanEndObject:
-			current = sliceToEnd(current)
-		case json.ScanBeginObject:
-			current = append(current, "")
-		case json.ScanError:
-			return nil, fmt.Errorf("Error reading JSON object at offset %v", offset)
+		if pending {
+			switch newOp {
+			case json.ScanBeginLiteral, json.ScanBeginObject, json.ScanBeginArray:
+				if !w.suppressed() && opts.wants(at, newOp != json.ScanBeginLiteral) {
+					rv = append(rv, w.pointerAt(at))
+				}
+				pending = false
+			}
+		}
+		if w.replaced {
+			rv = removeSubtree(rv, w.pointer())
 		}
 
-		if newOp == json.ScanBeginArray || newOp == json.ScanArrayValue ||
-			newOp == json.ScanObjectKey {
-			rv = append(rv, encodePointer(current))
+		if !w.suppressed() && (newOp == json.ScanBeginArray ||
+			newOp == json.ScanArrayValue || newOp == json.ScanObjectKey) {
+			switch {
+			case newOp == json.ScanBeginArray && !emptyIndex && emptyArray(data[w.offset:]):
+				// An empty array has no first element.
+			case opts.byKind():
+				pending = true
+				at = w.depth()
+			case opts.wants(w.depth(), false):
+				rv = append(rv, w.pointer())
+			}
+		}
+		if err := lim.checkPointers(len(rv), start+w.offset-1); err != nil {
+			return nil, err
 		}
 	}
+	if err := w.finish(); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+// removeSubtree removes ptr and the pointers below it from ps.
+func removeSubtree(ps []string, ptr string) []string {
+	rv := ps[:0]
+	for _, p := range ps {
+		if p != ptr && !strings.HasPrefix(p, ptr+"/") {
+			rv = append(rv, p)
+		}
+	}
+	return rv
+}
+
+// findState holds the scratch space for a pass through a document, so
+// it may be pooled and reused.
+type findState struct {
+	scan, value json.Scanner
+	// current holds the index within each open container, or -1
+	// for objects.
+	current []int
+	// tokens and indices hold the escaped tokens of the pointer
+	// sought, and each converted to an array index or -1.
+	tokens  []string
+	indices []int
+	// keyed is set for each token of the pointer sought whose key
+	// has appeared in the open object on the path.
+	keyed []bool
+	// containers and members hold the trie node of each open
+	// container and of its current member when finding many.
+	containers, members []*trieNode
+	// seen holds the member nodes whose keys have appeared in the
+	// open containers, those of each starting at seenStart.
+	seen      []*trieNode
+	seenStart []int
+	// cancel checks for cancellation of a scan with a context.
+	cancel canceller
+}
+
+var findStatePool = sync.Pool{
+	New: func() interface{} {
+		return &findState{}
+	},
+}
+
+func getFindState() *findState {
+	return findStatePool.Get().(*findState)
+}
+
+func putFindState(st *findState) {
+	st.cancel = canceller{}
+	st.current = st.current[:0]
+	st.tokens = st.tokens[:0]
+	st.indices = st.indices[:0]
+	st.keyed = st.keyed[:0]
+	for i := range st.containers {
+		st.containers[i] = nil
+	}
+	st.containers = st.containers[:0]
+	for i := range st.members {
+		st.members[i] = nil
+	}
+	st.members = st.members[:0]
+	for i := range st.seen {
+		st.seen[i] = nil
+	}
+	st.seen = st.seen[:0]
+	st.seenStart = st.seenStart[:0]
+	findStatePool.Put(st)
 }
 
-// FindMany finds several jsonpointers in one pass through the input.
-func FindMany(data []byte, paths []string) (map[string][]byte, error) {
-	tpaths := make([]string, 0, len(paths))
-	m := map[string][]byte{}
-	for _, p := range paths {
-		if p == "" {
-			m[p] = data
-		} else {
-			tpaths = append(tpaths, p)
+// addPath sets the pointer sought to a non-empty path.
+func (st *findState) addPath(path string) {
+	p := path[1:]
+	for {
+		i := strings.IndexByte(p, '/')
+		if i < 0 {
+			break
 		}
+		st.tokens = append(st.tokens, p[:i])
+		st.indices = append(st.indices, arrayIndex(p[:i]))
+		p = p[i+1:]
 	}
-	sort.Strings(tpaths)
+	st.tokens = append(st.tokens, p)
+	st.indices = append(st.indices, arrayIndex(p))
+}
 
-	scan := &json.Scanner{}
+// find scans data for the pointer sought with the given options.
+func (st *findState) find(data []byte, opts *FindOptions) ([]byte, error) {
+	lim, dup := &opts.Limits, opts.Duplicates
+	if err := lim.checkSize(data); err != nil {
+		return nil, err
+	}
+	scan := &st.scan
 	scan.Reset()
 
+	needle := len(st.tokens)
 	offset := 0
-	todo := len(tpaths)
 	beganLiteral := 0
-	matchedAt := 0
-	var current []string
-	for todo > 0 {
-		if offset >= len(data) {
-			break
+	// matched counts the open containers, from the root, whose
+	// current key or index agrees with the needle.
+	matched := 0
+	st.keyed = st.keyed[:0]
+	for range st.tokens {
+		st.keyed = append(st.keyed, false)
+	}
+	var found []byte
+	// skipped is set once a container has been skipped unvalidated.
+	skipped := false
+	st.cancel.restart()
+	for offset < len(data) {
+		if err := st.cancel.check(offset); err != nil {
+			return nil, err
 		}
 		newOp := scan.Step(scan, int(data[offset]))
 		offset++
 
+		depth := len(st.current)
+		before := matched
 		switch newOp {
 		case json.ScanBeginArray:
-			current = append(current, "0")
+			st.current = append(st.current, 0)
+			if matched == depth && depth < needle && st.indices[depth] == 0 {
+				matched++
+			}
 		case json.ScanObjectKey:
-			current[len(current)-1] = grokLiteral(data[beganLiteral-1 : offset-1])
+			if err := lim.checkKey(data[beganLiteral-1:offset-1], beganLiteral-1); err != nil {
+				return nil, err
+			}
+			if matched == depth {
+				matched--
+			}
+			if matched == depth-1 && depth <= needle &&
+				keyEquals(data[beganLiteral-1:offset-1], st.tokens[depth-1]) {
+				switch {
+				case !st.keyed[depth-1]:
+					st.keyed[depth-1] = true
+					matched++
+				case dup == DuplicateLast:
+					found = nil
+					matched++
+				case dup == DuplicateError:
+					return nil, &PointerError{"/" + strings.Join(st.tokens[:depth], "/"),
+						ErrDuplicateKey}
+				}
+			}
 		case json.ScanBeginLiteral:
 			beganLiteral = offset
 		case json.ScanArrayValue:
-			n := mustParseInt(current[len(current)-1])
-			current[len(current)-1] = strconv.Itoa(n + 1)
+			st.current[depth-1]++
+			if matched == depth {
+				matched--
+			}
+			if matched == depth-1 && depth <= needle &&
+				st.indices[depth-1] == st.current[depth-1] {
+				matched++
+			}
 		case json.ScanEndArray, json.ScanEndObject:
-			current = sliceToEnd(current)
+			st.current = st.current[:depth-1]
+			if matched == depth {
+				matched--
+			}
 		case json.ScanBeginObject:
-			current = append(current, "")
+			st.current = append(st.current, -1)
+			if matched == depth && depth < needle {
+				st.keyed[depth] = false
+			}
+		case json.ScanError:
+			return nil, syntaxError(data)
 		}
 
-		if newOp == json.ScanBeginArray || newOp == json.ScanArrayValue ||
-			newOp == json.ScanObjectKey {
-
-			if matchedAt < len(current)-1 {
-				continue
+		if newOp == json.ScanBeginArray || newOp == json.ScanBeginObject {
+			if err := lim.checkDepth(depth+1, offset-1); err != nil {
+				return nil, err
 			}
-			if matchedAt > len(current) {
-				matchedAt = len(current)
+			if before < depth || before >= needle {
+				// Nothing within this container can match, or
+				// it's within the value already found, so skip
+				// straight to its closing bracket.
+				var err error
+				offset, err = skipContainer(data, offset, depth+1, lim, &st.cancel)
+				if err != nil {
+					return nil, err
+				}
+				skipped = true
+				continue
 			}
+		}
 
-			currentStr := encodePointer(current)
-			off := sort.SearchStrings(tpaths, currentStr)
-			if off < len(tpaths) {
-				// Check to see if the path we're
-				// going down could even lead to a
-				// possible match.
-				if strings.HasPrefix(tpaths[off], currentStr) {
-					matchedAt++
-				}
-				// And if it's not an exact match, keep parsing.
-				if tpaths[off] != currentStr {
-					continue
+		if (newOp == json.ScanBeginArray || newOp == json.ScanArrayValue ||
+			newOp == json.ScanObjectKey) && matched == needle {
+			if emptyArray(data[offset:]) {
+				// special case an array offset miss
+				if dup == DuplicateFirst {
+					return nil, checkEnd(data, offset, st.current, &st.cancel)
 				}
-			} else {
-				// Fell of the end of the list, no possible match
 				continue
 			}
-
-			// At this point, we have an exact match, so grab it.
-			stmp := &json.Scanner{}
-			val, _, err := json.NextValue(data[offset:], stmp)
+			val, err := nextValue(data[offset:], &st.value)
 			if err != nil {
-				return m, err
+				return nil, syntaxError(data)
+			}
+			if dup == DuplicateFirst {
+				if err := checkEnd(data, offset, st.current, &st.cancel); err != nil {
+					return nil, err
+				}
+				return val, nil
+			}
+			found = val
+		}
+	}
+
+	if scan.EOF() == json.ScanError {
+		return nil, syntaxError(data)
+	}
+	if found == nil && skipped {
+		// A miss has to read the whole document anyway, so
+		// validate what was skipped rather than miss silently
+		// on malformed input.
+		if err := checkValid(data, &st.value, &st.cancel); err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+// checkValid reports a syntax error if data isn't valid JSON, reusing
+// scan, and checking for cancellation as it goes.
+func checkValid(data []byte, scan *json.Scanner, cancel *canceller) error {
+	scan.Reset()
+	cancel.restart()
+	for offset, c := range data {
+		if err := cancel.check(offset); err != nil {
+			return err
+		}
+		if scan.Step(scan, int(c)) == json.ScanError {
+			return syntaxError(data)
+		}
+	}
+	if scan.EOF() == json.ScanError {
+		return syntaxError(data)
+	}
+	return nil
+}
+
+// checkEnd reports a syntax error unless the containers open at
+// offset in data close in turn, followed only by whitespace, so a hit
+// is checked from where it was found rather than from the start.  open
+// holds each container's current index, or -1 for objects, as
+// findState.current does.  Only strings and brackets are examined.
+func checkEnd(data []byte, offset int, open []int, cancel *canceller) error {
+	for depth := len(open); depth > 0; depth-- {
+		end, err := skipContainer(data, offset, depth, nil, cancel)
+		if err != nil {
+			return err
+		}
+		closing := byte(']')
+		if open[depth-1] < 0 {
+			closing = '}'
+		}
+		if end == len(data) || data[end] != closing {
+			return syntaxError(data)
+		}
+		offset = end + 1
+	}
+	for ; offset < len(data); offset++ {
+		if !isSpace(rune(data[offset])) {
+			return syntaxError(data)
+		}
+	}
+	return nil
+}
+
+// emptyArray reports whether data is the remainder of an empty array.
+func emptyArray(data []byte) bool {
+	i := 0
+	for i < len(data) && isSpace(rune(data[i])) {
+		i++
+	}
+	return i < len(data) && data[i] == ']'
+}
+
+// nextValue is json.NextValue without the allocation the scanner
+// makes on seeing what follows the value.
+func nextValue(data []byte, scan *json.Scanner) ([]byte, error) {
+	end := valueEnd(data)
+	scan.Reset()
+	valid := true
+	for _, c := range data[:end] {
+		if scan.Step(scan, int(c)) == json.ScanError {
+			valid = false
+			break
+		}
+	}
+	if !valid || scan.EOF() != json.ScanEnd {
+		val, _, err := json.NextValue(data, scan)
+		return val, err
+	}
+	return data[:end], nil
+}
+
+// valueEnd returns the offset just past the value at the start of
+// data, without validating it.
+func valueEnd(data []byte) int {
+	i := 0
+	for i < len(data) && isSpace(rune(data[i])) {
+		i++
+	}
+	if i == len(data) {
+		return i
+	}
+	switch data[i] {
+	case '{', '[':
+		i, _ = skipContainer(data, i+1, 0, nil, nil)
+		i++
+	case '"':
+		for i++; i < len(data) && data[i] != '"'; i++ {
+			if data[i] == '\\' {
+				i++
 			}
-			m[currentStr] = val
-			todo--
 		}
+		i++
+	default:
+		for i < len(data) && !isSpace(rune(data[i])) &&
+			data[i] != ',' && data[i] != ']' && data[i] != '}' {
+			i++
+		}
+	}
+	if i > len(data) {
+		i = len(data)
 	}
+	return i
+}
+
+// Classes of the bytes skipContainer looks for.
+const (
+	skipOther = iota
+	skipQuote
+	skipOpen
+	skipClose
+)
 
-	return m, nil
+// skipClass holds the class of each byte.
+var skipClass = [256]byte{'"': skipQuote, '[': skipOpen, '{': skipOpen,
+	']': skipClose, '}': skipClose}
+
+// skipContainer returns the offset of the bracket closing the
+// container at depth whose contents begin at data[offset], or
+// len(data) if there is none.  Only strings and brackets are
+// examined, so the skipped contents aren't validated.
+//
+// Containers nesting deeper than lim allows are reported as a
+// LimitError, and cancellation as the canceller's error, along with
+// the offset reached.  Either lim or cancel may be nil.
+func skipContainer(data []byte, offset, depth int, lim *Limits,
+	cancel *canceller) (int, error) {
+
+	limit := -1
+	if lim != nil {
+		limit = lim.nesting(depth)
+	}
+	// stop is where to pause to check for cancellation.
+	stop := len(data)
+	if cancel != nil && cancel.ctx != nil {
+		stop = offset
+	}
+	nested := 0
+	i := offset
+	for {
+		for ; i < stop; i++ {
+			switch skipClass[data[i]] {
+			case skipOther:
+			case skipQuote:
+				for i++; i < len(data) && data[i] != '"'; i++ {
+					if data[i] == '\\' {
+						i++
+					}
+				}
+			case skipOpen:
+				nested++
+				if nested == limit+1 && limit >= 0 {
+					return i, &LimitError{"MaxDepth", lim.MaxDepth, i}
+				}
+			case skipClose:
+				if nested == 0 {
+					return i, nil
+				}
+				nested--
+			}
+		}
+		if i >= len(data) {
+			return len(data), nil
+		}
+		if err := cancel.check(i); err != nil {
+			return i, err
+		}
+		stop = cancel.next
+		if stop > len(data) {
+			stop = len(data)
+		}
+	}
 }
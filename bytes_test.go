@@ -32,6 +32,19 @@ var ptests = []struct {
 	{"/g/n/r", "where's tito?"},
 }
 
+func arreq(a, b []string) bool {
+	if len(a) == len(b) {
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
 func TestFindDecode(t *testing.T) {
 	in := []byte(objSrc)
 
@@ -216,8 +229,8 @@ func TestPointerCoder(t *testing.T) {
 	}
 
 	for k, v := range tests {
-		parsed := parsePointer(k)
-		encoded := encodePointer(v)
+		parsed := SplitLenient(k)
+		encoded := Join(v...)
 
 		if k != encoded {
 			t.Errorf("Expected to encode %#v as %#v, got %#v",
@@ -249,7 +262,7 @@ func TestCBugg406(t *testing.T) {
 func BenchmarkEncodePointer(b *testing.B) {
 	aPath := []string{"a", "ab", "a~0b", "a~1b", "a~0~1~0~1b"}
 	for i := 0; i < b.N; i++ {
-		encodePointer(aPath)
+		Join(aPath...)
 	}
 }
 
@@ -422,7 +435,7 @@ func TestEscape(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		esc := string(escape(test, nil))
+		esc := EscapeToken(test)
 		got := unescape(esc)
 		if got != test {
 			t.Errorf("unescape(escape(%q) [%q]) = %q", test, esc, got)
@@ -431,7 +444,7 @@ func TestEscape(t *testing.T) {
 
 	tf := func(s chars) bool {
 		uns := unescape(string(s))
-		got := string(escape(uns, nil))
+		got := EscapeToken(uns)
 		return got == string(s)
 	}
 	quick.Check(tf, nil)
@@ -565,7 +578,7 @@ func (c chars) Generate(rand *rand.Rand, _ int) reflect.Value {
 	for i := 0; i < size; i++ {
 		o = append(o, alphabet[rand.Intn(len(alphabet))])
 	}
-	s := chars(escape(string(o), nil))
+	s := chars(EscapeToken(string(o)))
 	return reflect.ValueOf(s)
 }
 
@@ -645,3 +658,186 @@ func BenchmarkDblReplacerTwo(b *testing.B) {
 		testDoubleReplacer(twoTestKey)
 	}
 }
+
+func TestExists(t *testing.T) {
+	doc := []byte(`{"a": null, "b": [null, {}], "c": []}`)
+	tests := []struct {
+		path  string
+		found bool
+	}{
+		{"", true},
+		{"/a", true},
+		{"/b/0", true},
+		{"/b/1", true},
+		{"/b/2", false},
+		{"/c/0", false},
+		{"/d", false},
+		{"/a/x", false},
+	}
+	for _, test := range tests {
+		found, err := Exists(doc, test.path)
+		if err != nil {
+			t.Errorf("Error checking %v: %v", test.path, err)
+		}
+		if found != test.found {
+			t.Errorf("On %v, expected found=%v, got %v",
+				test.path, test.found, found)
+		}
+	}
+}
+
+func TestListPointersWith(t *testing.T) {
+	doc := []byte(`{"a": {"b": [1, {"c": null}], "d": []}, "e": "f"}`)
+	tests := []struct {
+		opts ListOptions
+		exp  []string
+	}{
+		{ListOptions{},
+			[]string{"", "/a", "/a/b", "/a/b/0", "/a/b/1", "/a/b/1/c",
+				"/a/d", "/e"}},
+		{ListOptions{LeavesOnly: true},
+			[]string{"/a/b/0", "/a/b/1/c", "/e"}},
+		{ListOptions{ContainersOnly: true},
+			[]string{"", "/a", "/a/b", "/a/b/1", "/a/d"}},
+		{ListOptions{MaxDepth: 1},
+			[]string{"", "/a", "/e"}},
+		{ListOptions{MaxDepth: 2, LeavesOnly: true},
+			[]string{"/e"}},
+		{ListOptions{MaxDepth: 3},
+			[]string{"", "/a", "/a/b", "/a/b/0", "/a/b/1", "/a/d", "/e"}},
+		{ListOptions{Prefix: "/a/b"},
+			[]string{"/a/b", "/a/b/0", "/a/b/1", "/a/b/1/c"}},
+		{ListOptions{Prefix: "/a", MaxDepth: 2, ContainersOnly: true},
+			[]string{"/a", "/a/b", "/a/d"}},
+		{ListOptions{Prefix: "/a/d"}, []string{"/a/d"}},
+		{ListOptions{Prefix: "/a/d", MaxDepth: 3}, []string{"/a/d"}},
+		{ListOptions{Prefix: "/a/x"}, nil},
+		{ListOptions{Prefix: "/e", LeavesOnly: true}, []string{"/e"}},
+	}
+
+	for _, test := range tests {
+		got, err := ListPointersWith(doc, test.opts)
+		if err != nil {
+			t.Errorf("Error listing with %+v: %v", test.opts, err)
+		}
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("With %+v, expected %#v, got %#v", test.opts, test.exp, got)
+		}
+	}
+}
+
+func TestFindSkipsSubtrees(t *testing.T) {
+	doc := []byte(`{"a": {"b": [1, {"c": "}]\"{["}], "d": []},
+		"e": [[], [[2]], {"f": {}}], "g": "x"}`)
+	pointers, err := ListPointers(doc)
+	if err != nil {
+		t.Fatalf("Error listing pointers: %v", err)
+	}
+	pointers = append(pointers, "/a/b/2", "/e/1/0/1", "/nope", "/a/d/0")
+	for _, p := range pointers {
+		got, err := Find(doc, p)
+		if err != nil {
+			t.Errorf("Error finding %v: %v", p, err)
+			continue
+		}
+		many, err := FindMany(doc, []string{p})
+		if err != nil {
+			// FindMany trips over indexes into empty arrays.
+			if got != nil {
+				t.Errorf("On %v, expected nil, got %s", p, got)
+			}
+			continue
+		}
+		if string(got) != string(many[p]) {
+			t.Errorf("On %v, expected %s, got %s", p, many[p], got)
+		}
+	}
+}
+
+func BenchmarkLargeFindShallow(b *testing.B) {
+	b.SetBytes(int64(len(codeJSON)))
+
+	for i := 0; i < b.N; i++ {
+		found, err := Find(codeJSON,
+			"/tree/kids/0/kids/0/kids/1/kids/1/kids/3/name")
+		if err != nil || found == nil {
+			b.Fatalf("Didn't find it: %s/%v", found, err)
+		}
+	}
+}
+
+func BenchmarkLargeFindMissing(b *testing.B) {
+	b.SetBytes(int64(len(codeJSON)))
+
+	for i := 0; i < b.N; i++ {
+		found, err := Find(codeJSON, "/this/does/not/exist")
+		if err != nil || found != nil {
+			b.Fatalf("Found something: %s/%v", found, err)
+		}
+	}
+}
+
+func TestFindAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("pooling is unreliable under the race detector")
+	}
+	// Only keys with escapes need to be unquoted.
+	doc := []byte(`{"foo": ["bar", "baz"], "a/b": 1, "m~n": [{"x": null}],
+		"g/n/r": "has slash, will travel"}`)
+	c := MustCompile("/g~1n~1r")
+	tests := map[string]func(){
+		"Find": func() {
+			Find(doc, "/foo/1")
+		},
+		"Find escaped": func() {
+			Find(doc, "/m~0n/0/x")
+		},
+		"Find missing": func() {
+			Find(doc, "/foo/2")
+		},
+		"Compiled.Find": func() {
+			c.Find(doc)
+		},
+	}
+	for name, f := range tests {
+		if n := testing.AllocsPerRun(100, f); n != 0 {
+			t.Errorf("Expected no allocations in %v, got %v", name, n)
+		}
+	}
+}
+
+func TestManyPointersOverlapping(t *testing.T) {
+	doc := []byte(`{"a": {"b": [1, 2], "c~d": "x", "e\/f": true}, "g": []}`)
+	paths := []string{"/a", "/a/b/1", "/a/b", "/a/b/1", "/a/c~0d",
+		"/a/e~1f", "/g/0", "/nope"}
+	exp := map[string][]byte{
+		"/a":      []byte(` {"b": [1, 2], "c~d": "x", "e\/f": true}`),
+		"/a/b":    []byte(` [1, 2]`),
+		"/a/b/1":  []byte(` 2`),
+		"/a/c~0d": []byte(` "x"`),
+		"/a/e~1f": []byte(` true`),
+	}
+	got, err := FindMany(doc, paths)
+	if err != nil {
+		t.Fatalf("Error finding many: %v", err)
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %s, got %s", exp, got)
+	}
+}
+
+func TestNextValue(t *testing.T) {
+	tests := []string{
+		` 1, 2`, `"a\"]", 2`, ` [1, "]", {"a": [{}]}] ]`, `{"a": "}"}`,
+		`true}`, `null`, `-1.5e3 ]`, ` "unterminated`, `[1, 2`, `tru,`,
+		`{"a" 1}`, `[1 2]`, ``, ` `,
+	}
+	for _, test := range tests {
+		exp, _, experr := json.NextValue([]byte(test), &json.Scanner{})
+		got, err := nextValue([]byte(test), &json.Scanner{})
+		if string(got) != string(exp) || (err == nil) != (experr == nil) {
+			t.Errorf("On %q, expected %q/%v, got %q/%v",
+				test, exp, experr, got, err)
+		}
+	}
+}
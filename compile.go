@@ -0,0 +1,127 @@
+package jsonpointer
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Compiled is a JSON Pointer that has been parsed once so it may be
+// evaluated cheaply against many documents.
+type Compiled struct {
+	path   string
+	tokens []string
+	// escaped holds each token as it appears in the pointer.
+	escaped []string
+	// indices holds each token converted to an array index, or -1
+	// where the token can't address an array element.
+	indices []int
+}
+
+// Compile parses a JSON Pointer for repeated evaluation.
+func Compile(path string) (*Compiled, error) {
+	tokens, err := Split(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Compiled{path: path, tokens: tokens}
+	if path == "" {
+		return c, nil
+	}
+
+	c.escaped = strings.Split(path[1:], "/")
+	c.indices = make([]int, len(c.tokens))
+	for i, t := range c.escaped {
+		c.indices[i] = arrayIndex(t)
+	}
+	return c, nil
+}
+
+// MustCompile is like Compile, but panics on an invalid pointer.
+func MustCompile(path string) *Compiled {
+	c, err := Compile(path)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// String returns the source text of the pointer.
+func (c *Compiled) String() string {
+	return c.path
+}
+
+// arrayIndex converts a token to an array index as RFC6901 defines
+// it (no sign, no leading zeros), returning -1 if it isn't one.
+func arrayIndex(s string) int {
+	if s == "" || len(s) > 1 && s[0] == '0' {
+		return -1
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return -1
+		}
+		if n > (int(^uint(0)>>1)-int(c-'0'))/10 {
+			return -1
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n
+}
+
+// keyEquals compares a raw, quoted object key against a token as it
+// appears in a pointer, only unquoting the key if it contains escapes.
+func keyEquals(raw []byte, token string) bool {
+	// Whitespace before the colon is included in the raw key.
+	for isSpace(rune(raw[len(raw)-1])) {
+		raw = raw[:len(raw)-1]
+	}
+	key := raw[1 : len(raw)-1]
+	if bytes.IndexByte(key, '\\') >= 0 {
+		return grokLiteral(raw) == unescape(token)
+	}
+
+	i := 0
+	for j := 0; j < len(token); j++ {
+		c := token[j]
+		if c == '~' && j+1 < len(token) {
+			switch token[j+1] {
+			case '0':
+				j++
+			case '1':
+				c = '/'
+				j++
+			}
+		}
+		if i >= len(key) || key[i] != c {
+			return false
+		}
+		i++
+	}
+	return i == len(key)
+}
+
+// Find the section of raw JSON this pointer refers to.
+func (c *Compiled) Find(data []byte) ([]byte, error) {
+	if len(c.tokens) == 0 {
+		return data, nil
+	}
+
+	st := getFindState()
+	defer putFindState(st)
+	st.tokens = append(st.tokens, c.escaped...)
+	st.indices = append(st.indices, c.indices...)
+	return st.find(data, &FindOptions{})
+}
+
+// Get the value this pointer refers to.
+func (c *Compiled) Get(m interface{}) interface{} {
+	rv, _ := getPath(m, c.tokens, c.indices)
+	return rv
+}
+
+// Reflect gets the value this pointer refers to from a struct.
+func (c *Compiled) Reflect(o interface{}) interface{} {
+	return reflectPath(o, c.tokens, c.indices)
+}
@@ -0,0 +1,136 @@
+package jsonpointer
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestCompileInvalid(t *testing.T) {
+	c, err := Compile("foo")
+	if err == nil {
+		t.Errorf("Expected error compiling a relative pointer, got %v", c)
+	}
+}
+
+func TestArrayIndex(t *testing.T) {
+	tests := map[string]int{
+		"":                     -1,
+		"0":                    0,
+		"7":                    7,
+		"13":                   13,
+		"01":                   -1,
+		"-1":                   -1,
+		"+1":                   -1,
+		"1e3":                  -1,
+		"99999999999999999999": -1,
+	}
+	for in, exp := range tests {
+		if got := arrayIndex(in); got != exp {
+			t.Errorf("arrayIndex(%q) = %v, wanted %v", in, got, exp)
+		}
+	}
+}
+
+func TestCompiledFind(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/357.json")
+	if err != nil {
+		t.Fatalf("Error reading 357 data: %v", err)
+	}
+	docs := [][]byte{[]byte(objSrc), []byte(bug822src), bug3Data, data}
+	paths := []string{"/doesNotExist", "/address/0", "/address/1",
+		"/address2/1", "/address3/0", "/name", "/g/n/x", "/foo/1",
+		"/foo/2", "/k2", "/h/x"}
+	for _, test := range tests {
+		paths = append(paths, test.path)
+	}
+
+	for _, doc := range docs {
+		for _, p := range paths {
+			exp, experr := Find(doc, p)
+			got, err := MustCompile(p).Find(doc)
+			if !reflect.DeepEqual(err, experr) {
+				t.Errorf("Expected error %v at %v, got %v", experr, p, err)
+			}
+			if !reflect.DeepEqual(exp, got) {
+				t.Errorf("Expected %q at %v, got %q", exp, p, got)
+			}
+		}
+	}
+}
+
+func TestCompiledFindEscapedKey(t *testing.T) {
+	doc := []byte(`{"a/b": 1, "c\"d" : 2, "e~f": {"g": [3, 4]}}`)
+	tests := map[string]string{
+		"/a~1b":      " 1",
+		"/c\"d":      " 2",
+		"/e~0f/g/1":  " 4",
+		"/e~0f/g/01": "",
+	}
+	for p, exp := range tests {
+		got, err := MustCompile(p).Find(doc)
+		if err != nil {
+			t.Errorf("Error finding %v: %v", p, err)
+		}
+		if string(got) != exp {
+			t.Errorf("Expected %q at %v, got %q", exp, p, got)
+		}
+	}
+}
+
+func TestCompiledGet(t *testing.T) {
+	for _, test := range tests {
+		got := MustCompile(test.path).Get(obj)
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("On %v, expected %+v (%T), got %+v (%T)",
+				test.path, test.exp, test.exp, got, got)
+		}
+	}
+}
+
+func TestCompiledReflect(t *testing.T) {
+	paths := []string{"", "/name", "/aliases/1", "/addresses/0/Zip",
+		"/addresses/-1", "/AnActualArray/3", "/nestedmap/till~0duh",
+		"/MapIntKey/2", "/missing", "/aliases/01", "/AnActualArray/+3"}
+	for _, p := range paths {
+		exp := Reflect(input, p)
+		got := MustCompile(p).Reflect(input)
+		if !reflect.DeepEqual(got, exp) {
+			t.Errorf("On %v, expected %#v, got %#v", p, exp, got)
+		}
+	}
+
+	// Non-canonical indexes address nothing either way.
+	for _, p := range []string{"/aliases/01", "/AnActualArray/+3"} {
+		if got := Reflect(input, p); got != nil {
+			t.Errorf("On %v, expected nil, got %#v", p, got)
+		}
+	}
+}
+
+func BenchmarkCompiledFind(b *testing.B) {
+	obj := []byte(objSrc)
+	compiled := []*Compiled{}
+	for _, test := range tests {
+		compiled = append(compiled, MustCompile(test.path))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, c := range compiled {
+			c.Find(obj)
+		}
+	}
+}
+
+func BenchmarkCompiledLarge(b *testing.B) {
+	c := MustCompile("/tree/kids/0/kids/0/kids/1/kids/1/kids/3/name")
+	b.SetBytes(int64(len(codeJSON)))
+
+	for i := 0; i < b.N; i++ {
+		found, err := c.Find(codeJSON)
+		if err != nil || found == nil {
+			b.Fatalf("Didn't find the thing: %s/%v", found, err)
+		}
+	}
+}
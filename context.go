@@ -0,0 +1,57 @@
+package jsonpointer
+
+import (
+	"context"
+)
+
+// checkEvery is how many bytes are scanned between checks for
+// cancellation.
+const checkEvery = 64 << 10
+
+// canceller checks a context for cancellation as a scan progresses.
+type canceller struct {
+	ctx  context.Context
+	next int
+}
+
+// check returns the context's error, if any, when offset has reached
+// the next point to check.  It does nothing without a context.
+func (c *canceller) check(offset int) error {
+	if c.ctx == nil || offset < c.next {
+		return nil
+	}
+	c.next = offset + checkEvery
+	return c.ctx.Err()
+}
+
+// restart begins checking again for a scan from the start of the
+// input.
+func (c *canceller) restart() {
+	c.next = 0
+}
+
+// FindContext is FindWith, returning ctx's error if ctx is done
+// before the scan completes.
+func FindContext(ctx context.Context, data []byte, path string, opts FindOptions) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return data, nil
+	}
+
+	st := getFindState()
+	defer putFindState(st)
+	st.cancel.ctx = ctx
+	st.addPath(path)
+	return st.find(data, &opts)
+}
+
+// FindManyContext is FindManyWith, returning ctx's error if ctx is
+// done before the scan completes.
+func FindManyContext(ctx context.Context, data []byte, paths []string,
+	opts FindOptions) (map[string][]byte, error) {
+
+	found, err := NewFinderWith(paths, opts).FindContext(ctx, data)
+	return foundMap(paths, found), err
+}
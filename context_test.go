@@ -0,0 +1,88 @@
+package jsonpointer
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+// countdownContext is done once its Err method has been called n
+// times.
+type countdownContext struct {
+	context.Context
+	n int
+}
+
+func (c *countdownContext) Err() error {
+	c.n--
+	if c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if got, err := FindContext(ctx, []byte(objSrc), "/x", FindOptions{}); err != context.Canceled {
+		t.Errorf("Expected %v from FindContext, got %q, %v", context.Canceled, got, err)
+	}
+	got, err := FindManyContext(ctx, []byte(objSrc), []string{"/x", "/y"}, FindOptions{})
+	if err != context.Canceled {
+		t.Errorf("Expected %v from FindManyContext, got %q, %v", context.Canceled, got, err)
+	}
+	ptrs, err := ListPointersContext(ctx, []byte(objSrc), ListOptions{})
+	if err != context.Canceled {
+		t.Errorf("Expected %v from ListPointersContext, got %v, %v", context.Canceled, ptrs, err)
+	}
+}
+
+func TestContextDuringScan(t *testing.T) {
+	// Array elements can't be skipped, so each byte is scanned.
+	data := append([]byte{'['}, bytes.Repeat([]byte("1,"), 4*checkEvery)...)
+	data = append(data, '1', ']')
+
+	got, err := FindContext(&countdownContext{context.Background(), 3},
+		data, "/x", FindOptions{})
+	if err != context.Canceled {
+		t.Errorf("Expected %v from FindContext, got %q, %v", context.Canceled, got, err)
+	}
+	many, err := FindManyContext(&countdownContext{context.Background(), 3},
+		data, []string{"/x", "/0"}, FindOptions{Duplicates: DuplicateLast})
+	if err != context.Canceled {
+		t.Errorf("Expected %v from FindManyContext, got %q, %v", context.Canceled, many, err)
+	}
+	ptrs, err := ListPointersContext(&countdownContext{context.Background(), 3},
+		data, ListOptions{})
+	if err != context.Canceled || ptrs != nil {
+		t.Errorf("Expected %v from ListPointersContext, got %v pointers, %v",
+			context.Canceled, len(ptrs), err)
+	}
+
+	// Skipped containers are checked too.
+	skipped := []byte(`{"skip": [` + strings.Repeat(`"x", `, 4*checkEvery) + `"x"], "b": 1}`)
+	cancel := &canceller{ctx: &countdownContext{context.Background(), 2}}
+	end, err := skipContainer(skipped, 1, 1, nil, cancel)
+	if err != context.Canceled || end < 2*checkEvery || end > 3*checkEvery {
+		t.Errorf("Expected %v skipping just after %v, got %v at %v",
+			context.Canceled, 2*checkEvery, err, end)
+	}
+	got, err = FindContext(&countdownContext{context.Background(), 100},
+		skipped, "/b", FindOptions{})
+	if err != nil || string(got) != " 1" {
+		t.Errorf("Expected to find 1 after skipping, got %q, %v", got, err)
+	}
+
+	// With enough checks allowed, the scans complete.
+	ctx := &countdownContext{context.Background(), 100}
+	if got, err := FindContext(ctx, data, "/3", FindOptions{}); err != nil || string(got) != "1" {
+		t.Errorf("Expected to find 1, got %q, %v", got, err)
+	}
+	ctx = &countdownContext{context.Background(), 100}
+	if ptrs, err := ListPointersContext(ctx, data, ListOptions{}); err != nil ||
+		len(ptrs) != 4*checkEvery+2 {
+		t.Errorf("Expected %v pointers, got %v, %v", 4*checkEvery+2, len(ptrs), err)
+	}
+}
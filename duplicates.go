@@ -0,0 +1,79 @@
+package jsonpointer
+
+import (
+	"fmt"
+
+	"github.com/dustin/gojson"
+)
+
+// DuplicatePolicy decides which of several members of an object with
+// the same key is used.  RFC8259 leaves this undefined.
+type DuplicatePolicy int
+
+const (
+	// DuplicateFirst uses the first member with a key, ignoring
+	// the rest, even when only a later one holds the rest of the
+	// path.  It's the default.
+	DuplicateFirst DuplicatePolicy = iota
+	// DuplicateLast uses the last member with a key, as
+	// encoding/json does when decoding.
+	DuplicateLast
+	// DuplicateError reports ErrDuplicateKey.
+	DuplicateError
+)
+
+func (p DuplicatePolicy) String() string {
+	switch p {
+	case DuplicateFirst:
+		return "DuplicateFirst"
+	case DuplicateLast:
+		return "DuplicateLast"
+	case DuplicateError:
+		return "DuplicateError"
+	}
+	return fmt.Sprintf("DuplicatePolicy(%d)", int(p))
+}
+
+// FindOptions controls how raw JSON is searched.
+type FindOptions struct {
+	// Duplicates decides between members with the same key on
+	// the path to a value.  Duplicates elsewhere are ignored.  By
+	// default only the first member with a key is searched, so
+	// /a/b isn't found in {"a": {}, "a": {"b": 1}}.
+	Duplicates DuplicatePolicy
+	// Limits bounds the input read.
+	Limits Limits
+}
+
+// FindWith is Find with options.
+func FindWith(data []byte, path string, opts FindOptions) ([]byte, error) {
+	if path == "" {
+		return data, nil
+	}
+
+	st := getFindState()
+	defer putFindState(st)
+	st.addPath(path)
+	return st.find(data, &opts)
+}
+
+// DuplicateKeys lists the pointer of every member of an object whose
+// key appeared earlier in the same object, in document order.
+func DuplicateKeys(data []byte) ([]string, error) {
+	var rv []string
+	w := newWalker(data)
+	w.trackDuplicates(DuplicateFirst)
+	for !w.done() {
+		op, err := w.step()
+		if err != nil {
+			return nil, err
+		}
+		if op == json.ScanObjectKey && w.duplicate {
+			rv = append(rv, w.pointer())
+		}
+	}
+	if err := w.finish(); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
@@ -0,0 +1,144 @@
+package jsonpointer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const dupSrc = `{"a": {"x": 1, "y": 2}, "b": [1, {"c": 3, "c": 4}], "a": {"x": 5}}`
+
+func TestFindWithDuplicates(t *testing.T) {
+	tests := []struct {
+		path string
+		dup  DuplicatePolicy
+		exp  string
+		err  string
+	}{
+		{"/a/x", DuplicateFirst, " 1", ""},
+		{"/a/y", DuplicateFirst, " 2", ""},
+		{"/b/1/c", DuplicateFirst, " 3", ""},
+		{"/a/x", DuplicateLast, " 5", ""},
+		{"/a/y", DuplicateLast, "", ""},
+		{"/b/1/c", DuplicateLast, " 4", ""},
+		{"/b/0", DuplicateLast, "1", ""},
+		{"/a/x", DuplicateError, "", "/a"},
+		{"/b/1/c", DuplicateError, "", "/b/1/c"},
+		{"/b/0", DuplicateError, "1", ""},
+	}
+
+	for _, test := range tests {
+		opts := FindOptions{Duplicates: test.dup}
+		check := func(name string, got []byte, err error) {
+			if test.err != "" {
+				var perr *PointerError
+				if !errors.As(err, &perr) || perr.Pointer != test.err ||
+					!errors.Is(err, ErrDuplicateKey) {
+					t.Errorf("%v %v with %v, expected duplicate at %v, got %q/%v",
+						name, test.path, test.dup, test.err, got, err)
+				}
+				return
+			}
+			if err != nil || string(got) != test.exp {
+				t.Errorf("%v %v with %v, expected %q, got %q/%v",
+					name, test.path, test.dup, test.exp, got, err)
+			}
+		}
+
+		got, err := FindWith([]byte(dupSrc), test.path, opts)
+		check("FindWith", got, err)
+		m, err := FindManyWith([]byte(dupSrc), []string{test.path, "/b"}, opts)
+		check("FindManyWith", m[test.path], err)
+	}
+}
+
+func TestFindDeepDuplicates(t *testing.T) {
+	// The duplicates are 66 levels down, within 65 objects.
+	doc := []byte(strings.Repeat(`{"a": `, 65) + `{"a": 1, "a": 2}` +
+		strings.Repeat("}", 65))
+	path := strings.Repeat("/a", 66)
+	tests := []struct {
+		dup DuplicatePolicy
+		exp string
+	}{
+		{DuplicateFirst, " 1"},
+		{DuplicateLast, " 2"},
+		{DuplicateError, ""},
+	}
+	for _, test := range tests {
+		opts := FindOptions{Duplicates: test.dup}
+		got, err := FindWith(doc, path, opts)
+		many, merr := FindManyWith(doc, []string{path}, opts)
+		if test.exp == "" {
+			if !errors.Is(err, ErrDuplicateKey) || !errors.Is(merr, ErrDuplicateKey) {
+				t.Errorf("With %v, expected duplicate key errors, got %q/%v and %q/%v",
+					test.dup, got, err, many, merr)
+			}
+			continue
+		}
+		if err != nil || string(got) != test.exp || merr != nil || string(many[path]) != test.exp {
+			t.Errorf("With %v, expected %q, got %q/%v and %q/%v",
+				test.dup, test.exp, got, err, many[path], merr)
+		}
+	}
+}
+
+func TestFindFirstDuplicateOnly(t *testing.T) {
+	doc := []byte(`{"a": {}, "a": {"b": 1}}`)
+	if got, err := Find(doc, "/a/b"); err != nil || got != nil {
+		t.Errorf("Expected nothing in the first a, got %q, %v", got, err)
+	}
+	if got, err := FindMany(doc, []string{"/a/b"}); err != nil || len(got) != 0 {
+		t.Errorf("Expected nothing in the first a from FindMany, got %q, %v", got, err)
+	}
+	got, err := FindWith(doc, "/a/b", FindOptions{Duplicates: DuplicateLast})
+	if err != nil || string(got) != " 1" {
+		t.Errorf("Expected 1 in the last a, got %q, %v", got, err)
+	}
+}
+
+func TestListPointersDuplicates(t *testing.T) {
+	tests := []struct {
+		dup DuplicatePolicy
+		exp []string
+	}{
+		{DuplicateFirst, []string{"", "/a", "/a/x", "/a/y", "/b", "/b/0",
+			"/b/1", "/b/1/c"}},
+		{DuplicateLast, []string{"", "/b", "/b/0", "/b/1", "/b/1/c",
+			"/a", "/a/x"}},
+	}
+	for _, test := range tests {
+		got, err := ListPointersWith([]byte(dupSrc), ListOptions{Duplicates: test.dup})
+		if err != nil {
+			t.Errorf("Error listing with %v: %v", test.dup, err)
+		}
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("With %v, expected %v, got %v", test.dup, test.exp, got)
+		}
+	}
+
+	got, err := ListPointersWith([]byte(dupSrc), ListOptions{Duplicates: DuplicateError})
+	if !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("Expected duplicate key error, got %v/%v", got, err)
+	}
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	got, err := DuplicateKeys([]byte(dupSrc))
+	if err != nil {
+		t.Fatalf("Error finding duplicates: %v", err)
+	}
+	exp := []string{"/b/1/c", "/a"}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %v, got %v", exp, got)
+	}
+
+	got, err = DuplicateKeys([]byte(`{"a": [1, {"b": 2}]}`))
+	if err != nil || got != nil {
+		t.Errorf("Expected no duplicates, got %v/%v", got, err)
+	}
+	if _, err := DuplicateKeys([]byte(`{"a": [}`)); err == nil {
+		t.Errorf("Expected error on broken JSON")
+	}
+}
@@ -0,0 +1,137 @@
+package jsonpointer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/dustin/gojson"
+)
+
+// ErrNotFound is reported when a pointer doesn't refer to any value.
+var ErrNotFound = errors.New("value not found")
+
+// ErrDuplicateKey is reported when an object has the same key more
+// than once and the duplicate policy forbids it.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// ErrConflict is reported when values can't all be placed in one
+// document, e.g. when one is to go within another that isn't a
+// container.
+var ErrConflict = errors.New("conflicting placement")
+
+// ErrLimitExceeded is reported, wrapped in a LimitError, when input
+// exceeds one of the Limits.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// PointerError records an error evaluating a particular pointer.
+type PointerError struct {
+	Pointer string
+	Err     error
+}
+
+func (e *PointerError) Error() string {
+	return fmt.Sprintf("%v at %q", e.Err, e.Pointer)
+}
+
+// Unwrap returns the underlying error.
+func (e *PointerError) Unwrap() error {
+	return e.Err
+}
+
+// LimitError records which of the Limits input exceeded.
+type LimitError struct {
+	// Limit is the name of the field of Limits exceeded.
+	Limit string
+	// Max is the value of that field.
+	Max int
+	// Offset is the byte offset in the input where the limit was
+	// exceeded.
+	Offset int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%v: %v of %v at offset %v",
+		ErrLimitExceeded, e.Limit, e.Max, e.Offset)
+}
+
+// Unwrap returns ErrLimitExceeded.
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// SyntaxError describes invalid JSON.
+type SyntaxError struct {
+	// Offset is the byte offset of the offending character, or the
+	// length of the input if it ended early.
+	Offset int
+	// Line and Column locate the offending character, counting from
+	// 1.  Columns count bytes.
+	Line, Column int
+	// Pointer refers to the container enclosing the error.
+	Pointer string
+	// Char is the offending character, or 0 if the input ended early.
+	Char rune
+	// Reason is the scanner's description of the error.
+	Reason string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v at line %v, column %v (in %q)",
+		e.Reason, e.Line, e.Column, e.Pointer)
+}
+
+// syntaxError rescans invalid JSON to describe its first error.
+func syntaxError(data []byte) error {
+	// The walker is stepped by hand, since its step reports
+	// errors with this.
+	w := newWalker(data)
+	scan := &w.scan
+	for !w.done() {
+		c := data[w.offset]
+		newOp := scan.Step(scan, int(c))
+		if newOp == json.ScanError || newOp == json.ScanEnd && !isSpace(rune(c)) {
+			// The scanner notes garbage after the top-level
+			// value, but only reports it on the next byte.
+			break
+		}
+		w.offset++
+		w.track(newOp)
+	}
+	offset := w.offset
+	if offset == len(data) && scan.EOF() != json.ScanError {
+		return nil
+	}
+
+	e := &SyntaxError{Offset: offset, Line: 1}
+	if len(w.tokens) > 0 {
+		e.Pointer = Join(w.tokens[:len(w.tokens)-1]...)
+	}
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	e.Line += bytes.Count(data[:lineStart], []byte{'\n'})
+	e.Column = offset - lineStart + 1
+	if offset < len(data) {
+		e.Char, _ = utf8.DecodeRune(data[offset:])
+	}
+	if _, _, err := json.NextValue(data, scan); err != nil {
+		e.Reason = err.Error()
+	} else {
+		e.Reason = "invalid character " + quoteChar(data[offset]) +
+			" after top-level value"
+	}
+	return e
+}
+
+// quoteChar formats c as the scanner does in its errors.
+func quoteChar(c byte) string {
+	switch c {
+	case '\'':
+		return `'\''`
+	case '"':
+		return `'"'`
+	}
+	s := strconv.Quote(string(rune(c)))
+	return "'" + s[1:len(s)-1] + "'"
+}
@@ -0,0 +1,124 @@
+package jsonpointer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPointerError(t *testing.T) {
+	var err error = &PointerError{"/a~1b", ErrNotFound}
+	exp := `value not found at "/a~1b"`
+	if err.Error() != exp {
+		t.Errorf("Expected %q, got %q", exp, err.Error())
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected %v to be ErrNotFound", err)
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	tests := []struct {
+		in      string
+		offset  int
+		line    int
+		column  int
+		pointer string
+		char    rune
+		reason  string
+	}{
+		{`{"a" 1}`, 5, 1, 6, "", '1',
+			"invalid character '1' after object key"},
+		{"{\"a\": {\n  \"b\": [1,\n  2 x]}}", 23, 3, 5, "/a/b", 'x',
+			"invalid character 'x' after array element"},
+		{`{"a": [1, 2`, 11, 1, 12, "/a", 0,
+			"unexpected end of JSON input"},
+		{`{"a": 1} x`, 9, 1, 10, "", 'x',
+			"invalid character 'x' after top-level value"},
+		{`{"a": 1}}`, 8, 1, 9, "", '}',
+			"invalid character '}' after top-level value"},
+		{`{"a": é}`, 6, 1, 7, "", 'é',
+			"invalid character 'Ã' looking for beginning of value"},
+	}
+
+	for _, test := range tests {
+		err := syntaxError([]byte(test.in))
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("Expected a SyntaxError for %q, got %v", test.in, err)
+			continue
+		}
+		if se.Offset != test.offset || se.Line != test.line ||
+			se.Column != test.column || se.Pointer != test.pointer ||
+			se.Char != test.char || se.Reason != test.reason {
+			t.Errorf("Expected %v:%v:%v %q %q %q for %q, got %v:%v:%v %q %q %q",
+				test.offset, test.line, test.column, test.pointer, test.char, test.reason,
+				test.in,
+				se.Offset, se.Line, se.Column, se.Pointer, se.Char, se.Reason)
+		}
+	}
+
+	if err := syntaxError([]byte(`{"a": [1]} `)); err != nil {
+		t.Errorf("Expected no error for valid JSON, got %v", err)
+	}
+
+	err := syntaxError([]byte(`{"a": {"b" 1}}`))
+	exp := `invalid character '1' after object key at line 1, column 12 (in "/a")`
+	if err == nil || err.Error() != exp {
+		t.Errorf("Expected %q, got %v", exp, err)
+	}
+}
+
+func TestSyntaxErrorReported(t *testing.T) {
+	tests := []struct {
+		in string
+		// atEnd is set where the error is caught even after a hit.
+		atEnd bool
+	}{
+		{`{"a": 1, "b" 2}`, false},
+		{`{"a": 1, "b": 2`, true},
+		{`{"a": 1, "b": 2} x`, true},
+		{`{"a": 1, "b": 2}{}`, true},
+		{`{"a": 1, "b": 2}}`, true},
+		{`{"a":1}{"b":2}`, true},
+		{`{"a":1}}`, true},
+		// Errors in containers skipped by a miss are still found.
+		{`{"a": [1, 2 x]}`, false},
+		{`{"a": {"x" 1}, "b": 2}`, false},
+		{`{"a": 1, "b": 2]`, true},
+		{`{"a": {"x": 1}, "b": [2}`, true},
+		{`{"a": {"x": 1}, "b": {"y": [2}}`, true},
+	}
+
+	for _, tc := range tests {
+		test := tc.in
+		var se *SyntaxError
+		paths := []string{"/c"}
+		if tc.atEnd {
+			paths = append(paths, "/a", "/b")
+		}
+		for _, p := range paths {
+			v, err := Find([]byte(test), p)
+			if !errors.As(err, &se) || v != nil {
+				t.Errorf("Expected SyntaxError finding %v in %q, got %q, %v",
+					p, test, v, err)
+			}
+			if ok, err := Exists([]byte(test), p); ok || err == nil {
+				t.Errorf("Expected error checking %v exists in %q, got %v, %v",
+					p, test, ok, err)
+			}
+		}
+		for _, ps := range [][]string{{"/a", "/c"}, {"/a", "/b"}} {
+			m, err := FindMany([]byte(test), ps)
+			if !errors.As(err, &se) || len(m) > 0 {
+				t.Errorf("Expected SyntaxError from FindMany %v in %q, got %q, %v",
+					ps, test, m, err)
+			}
+		}
+		if _, err := ListPointers([]byte(test)); !errors.As(err, &se) {
+			t.Errorf("Expected SyntaxError from ListPointers in %q, got %v", test, err)
+		}
+		if m, err := FindAll([]byte(test), "/*"); !errors.As(err, &se) || m != nil {
+			t.Errorf("Expected SyntaxError from FindAll in %q, got %q, %v", test, m, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package jsonpointer
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// A Finder finds a fixed set of pointers in many documents.  It is
+// safe for concurrent use.
+type Finder struct {
+	paths []string
+	root  *trieNode
+	opts  FindOptions
+}
+
+// NewFinder prepares to find the given pointers.
+func NewFinder(paths []string) *Finder {
+	return NewFinderWith(paths, FindOptions{})
+}
+
+// NewFinderWith prepares to find the given pointers with options.
+func NewFinderWith(paths []string, opts FindOptions) *Finder {
+	return &Finder{paths, newTrie(paths), opts}
+}
+
+// Find finds the pointers in one pass through a document, returning
+// the value of each in the order given to NewFinder, or nil where
+// it's missing.  No values are returned along with an error.
+func (f *Finder) Find(data []byte) ([][]byte, error) {
+	st := getFindState()
+	defer putFindState(st)
+	return f.find(data, st)
+}
+
+// FindContext is Find, returning ctx's error if ctx is done before
+// the scan completes.
+func (f *Finder) FindContext(ctx context.Context, data []byte) ([][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return make([][]byte, len(f.paths)), err
+	}
+	st := getFindState()
+	defer putFindState(st)
+	st.cancel.ctx = ctx
+	return f.find(data, st)
+}
+
+func (f *Finder) find(data []byte, st *findState) ([][]byte, error) {
+	found := make([][]byte, len(f.paths))
+	for _, i := range f.root.paths {
+		found[i] = data
+	}
+	err := st.findMany(data, f.root, found, len(f.paths)-len(f.root.paths), &f.opts)
+	if err != nil {
+		for i := range found {
+			found[i] = nil
+		}
+	}
+	return found, err
+}
+
+// BatchResult is the outcome of finding pointers in one document.
+type BatchResult struct {
+	// Values holds the value of each pointer, or nil where it's
+	// missing.
+	Values [][]byte
+	// Err is any error reading the document.
+	Err error
+}
+
+// Batch finds the pointers in each of docs using up to workers
+// goroutines (GOMAXPROCS if workers isn't positive), returning the
+// results in the order of docs.  If ctx is done before every document
+// has been read, ctx's error is returned, and the remaining results
+// are left empty or hold ctx's error where a document was being read.
+func (f *Finder) Batch(ctx context.Context, docs [][]byte, workers int) ([]BatchResult, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(docs) {
+		workers = len(docs)
+	}
+
+	rv := make([]BatchResult, len(docs))
+	var next int64 = -1
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			st := getFindState()
+			defer putFindState(st)
+			st.cancel.ctx = ctx
+			for ctx.Err() == nil {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(docs) {
+					return
+				}
+				rv[i].Values, rv[i].Err = f.find(docs[i], st)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return rv, ctx.Err()
+}
+
+// BatchFind finds the same pointers in many documents concurrently.
+// See Finder.Batch.
+func BatchFind(ctx context.Context, docs [][]byte, paths []string, workers int) ([]BatchResult, error) {
+	return NewFinder(paths).Batch(ctx, docs, workers)
+}
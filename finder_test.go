@@ -0,0 +1,82 @@
+package jsonpointer
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestFinder(t *testing.T) {
+	f := NewFinder([]string{"/a", "", "/b/1"})
+	doc := []byte(`{"b": [1, 2], "a": "x"}`)
+	got, err := f.Find(doc)
+	if err != nil {
+		t.Fatalf("Error finding: %v", err)
+	}
+	exp := [][]byte{[]byte(` "x"`), doc, []byte(` 2`)}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %q, got %q", exp, got)
+	}
+}
+
+func TestBatchFind(t *testing.T) {
+	var docs [][]byte
+	for i := 0; i < 100; i++ {
+		docs = append(docs, []byte(`{"n": `+strconv.Itoa(i)+`, "x": [true]}`))
+	}
+	docs[42] = []byte(`{"n": 42, "x": [}`)
+
+	got, err := BatchFind(context.Background(), docs, []string{"/n", "/x/0"}, 4)
+	if err != nil {
+		t.Fatalf("Error finding batch: %v", err)
+	}
+	if len(got) != len(docs) {
+		t.Fatalf("Expected %v results, got %v", len(docs), len(got))
+	}
+	for i, r := range got {
+		if i == 42 {
+			if r.Err == nil {
+				t.Errorf("Expected error on broken document, got %q", r.Values)
+			}
+			continue
+		}
+		if r.Err != nil {
+			t.Errorf("Error on document %v: %v", i, r.Err)
+			continue
+		}
+		if string(r.Values[0]) != " "+strconv.Itoa(i) || string(r.Values[1]) != "true" {
+			t.Errorf("On document %v, got %q", i, r.Values)
+		}
+	}
+}
+
+func TestBatchFindCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	docs := [][]byte{[]byte(`{"a": 1}`), []byte(`{"a": 2}`)}
+	got, err := BatchFind(ctx, docs, []string{"/a"}, 0)
+	if err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	for i, r := range got {
+		if r.Values != nil {
+			t.Errorf("Expected nothing for document %v, got %q", i, r.Values)
+		}
+	}
+}
+
+func BenchmarkBatchFind(b *testing.B) {
+	docs := make([][]byte, 16)
+	for i := range docs {
+		docs[i] = codeJSON
+	}
+	f := NewFinder([]string{"/tree/kids/0/kids/0/name", "/tree/name"})
+	b.SetBytes(int64(len(codeJSON) * len(docs)))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := f.Batch(context.Background(), docs, 0); err != nil {
+			b.Fatalf("Error finding batch: %v", err)
+		}
+	}
+}
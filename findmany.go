@@ -0,0 +1,265 @@
+package jsonpointer
+
+import (
+	"bytes"
+
+	"github.com/dustin/gojson"
+)
+
+// FindMany finds several jsonpointers in one pass through the input.
+func FindMany(data []byte, paths []string) (map[string][]byte, error) {
+	return FindManyWith(data, paths, FindOptions{})
+}
+
+// FindManyOrdered finds several jsonpointers in one pass through the
+// input, returning the value of each in the order requested, or nil
+// where it's missing.  Pointers may overlap, e.g. /a and /a/b, and
+// may be repeated.
+func FindManyOrdered(data []byte, paths []string) ([][]byte, error) {
+	return NewFinder(paths).Find(data)
+}
+
+// FindManyWith is FindMany with options.
+func FindManyWith(data []byte, paths []string, opts FindOptions) (map[string][]byte, error) {
+	found, err := NewFinderWith(paths, opts).Find(data)
+	return foundMap(paths, found), err
+}
+
+// foundMap maps each of paths to its value found, if any.
+func foundMap(paths []string, found [][]byte) map[string][]byte {
+	m := map[string][]byte{}
+	for i, p := range paths {
+		if found[i] != nil {
+			m[p] = found[i]
+		}
+	}
+	return m
+}
+
+// trieNode is a token of one or more of the pointers sought by
+// FindMany.
+type trieNode struct {
+	children map[string]*trieNode
+	// elems holds the children whose tokens are array indices.
+	elems map[int]*trieNode
+	// paths holds the positions of the pointers ending here.
+	paths []int
+	// pointer is the pointer to this node.
+	pointer string
+}
+
+func newTrie(paths []string) *trieNode {
+	root := &trieNode{}
+	for i, p := range paths {
+		n := root
+		if p != "" {
+			for _, t := range SplitLenient(p) {
+				n = n.child(t)
+			}
+		}
+		n.paths = append(n.paths, i)
+	}
+	return root
+}
+
+func (n *trieNode) child(t string) *trieNode {
+	if c, ok := n.children[t]; ok {
+		return c
+	}
+	c := &trieNode{pointer: n.pointer + "/" + EscapeToken(t)}
+	if n.children == nil {
+		n.children = map[string]*trieNode{}
+	}
+	n.children[t] = c
+	if i := arrayIndex(t); i >= 0 {
+		if n.elems == nil {
+			n.elems = map[int]*trieNode{}
+		}
+		n.elems[i] = c
+	}
+	return c
+}
+
+// each calls f with the position of every pointer at or below n.
+func (n *trieNode) each(f func(int)) {
+	for _, i := range n.paths {
+		f(i)
+	}
+	for _, c := range n.children {
+		c.each(f)
+	}
+}
+
+// member looks up the child for a raw, quoted object key.
+func (n *trieNode) member(raw []byte) *trieNode {
+	if n.children == nil {
+		return nil
+	}
+	// Whitespace before the colon is included in the raw key.
+	for isSpace(rune(raw[len(raw)-1])) {
+		raw = raw[:len(raw)-1]
+	}
+	if bytes.IndexByte(raw, '\\') >= 0 {
+		return n.children[grokLiteral(raw)]
+	}
+	return n.children[string(raw[1:len(raw)-1])]
+}
+
+// findMany scans data for the pointers in the trie below root,
+// storing each value found, with the given options.  Under
+// DuplicateFirst it stops once todo have been found.
+func (st *findState) findMany(data []byte, root *trieNode, found [][]byte, todo int,
+	opts *FindOptions) error {
+
+	lim, dup := &opts.Limits, opts.Duplicates
+	if err := lim.checkSize(data); err != nil {
+		return err
+	}
+	scan := &st.scan
+	scan.Reset()
+	st.current = st.current[:0]
+	st.containers = st.containers[:0]
+	st.members = st.members[:0]
+	st.seen = st.seen[:0]
+	st.seenStart = st.seenStart[:0]
+
+	offset := 0
+	beganLiteral := 0
+	// skipped is set once a container has been skipped unvalidated.
+	skipped := false
+	st.cancel.restart()
+	for (todo > 0 || dup != DuplicateFirst) && offset < len(data) {
+		if err := st.cancel.check(offset); err != nil {
+			return err
+		}
+		newOp := scan.Step(scan, int(data[offset]))
+		offset++
+
+		depth := len(st.current)
+		var hit *trieNode
+		switch newOp {
+		case json.ScanBeginArray, json.ScanBeginObject:
+			if err := lim.checkDepth(depth+1, offset-1); err != nil {
+				return err
+			}
+			n := root
+			if depth > 0 {
+				n = st.members[depth-1]
+			}
+			st.containers = append(st.containers, n)
+			st.seenStart = append(st.seenStart, len(st.seen))
+			if n == nil || n.children == nil {
+				// Nothing within this container is sought, so
+				// skip straight to its closing bracket.
+				st.current = append(st.current, -1)
+				st.members = append(st.members, nil)
+				var err error
+				offset, err = skipContainer(data, offset, depth+1, lim, &st.cancel)
+				if err != nil {
+					return err
+				}
+				skipped = true
+				continue
+			}
+			if newOp == json.ScanBeginArray {
+				st.current = append(st.current, 0)
+				hit = n.elems[0]
+			} else {
+				st.current = append(st.current, -1)
+			}
+			st.members = append(st.members, hit)
+		case json.ScanObjectKey:
+			if err := lim.checkKey(data[beganLiteral-1:offset-1], beganLiteral-1); err != nil {
+				return err
+			}
+			hit = st.containers[depth-1].member(data[beganLiteral-1 : offset-1])
+			if hit != nil && st.seenKey(hit, st.seenStart[depth-1]) {
+				switch dup {
+				case DuplicateFirst:
+					hit = nil
+				case DuplicateLast:
+					hit.each(func(i int) {
+						if found[i] != nil {
+							found[i] = nil
+							todo++
+						}
+					})
+				case DuplicateError:
+					return &PointerError{hit.pointer, ErrDuplicateKey}
+				}
+			}
+			st.members[depth-1] = hit
+		case json.ScanBeginLiteral:
+			beganLiteral = offset
+		case json.ScanArrayValue:
+			st.current[depth-1]++
+			hit = st.containers[depth-1].elems[st.current[depth-1]]
+			st.members[depth-1] = hit
+		case json.ScanEndArray, json.ScanEndObject:
+			st.current = st.current[:depth-1]
+			st.containers = st.containers[:depth-1]
+			st.members = st.members[:depth-1]
+			st.seen = st.seen[:st.seenStart[depth-1]]
+			st.seenStart = st.seenStart[:depth-1]
+		case json.ScanError:
+			return syntaxError(data)
+		}
+
+		if hit == nil || len(hit.paths) == 0 || found[hit.paths[0]] != nil ||
+			emptyArray(data[offset:]) {
+			continue
+		}
+		val, err := nextValue(data[offset:], &st.value)
+		if err != nil {
+			return syntaxError(data)
+		}
+		for _, i := range hit.paths {
+			found[i] = val
+			todo--
+		}
+	}
+
+	if offset == 0 {
+		// Only the whole document is sought, if anything, so
+		// check it as the rest of one would be after a hit.
+		for offset < len(data) && isSpace(rune(data[offset])) {
+			offset++
+		}
+		switch {
+		case offset == len(data):
+			return syntaxError(data)
+		case data[offset] == '{':
+			st.current = append(st.current, -1)
+			offset++
+		case data[offset] == '[':
+			st.current = append(st.current, 0)
+			offset++
+		default:
+			return checkValid(data, &st.value, &st.cancel)
+		}
+	}
+	if offset < len(data) {
+		return checkEnd(data, offset, st.current, &st.cancel)
+	}
+	if scan.EOF() == json.ScanError {
+		return syntaxError(data)
+	}
+	if todo > 0 && skipped {
+		// Some pointer was missed after reading the whole
+		// document, so validate what was skipped too.
+		return checkValid(data, &st.value, &st.cancel)
+	}
+	return nil
+}
+
+// seenKey reports whether n has been seen among the keys of the
+// container whose seen keys begin at start, noting it if not.
+func (st *findState) seenKey(n *trieNode, start int) bool {
+	for _, s := range st.seen[start:] {
+		if s == n {
+			return true
+		}
+	}
+	st.seen = append(st.seen, n)
+	return false
+}
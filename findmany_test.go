@@ -0,0 +1,83 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestFindManyOrdered(t *testing.T) {
+	doc := []byte(`{"a": {"b": [1, {"c": null}]}, "d": [], "e": "x"}`)
+	paths := []string{"/e", "/a/b/1/c", "/nope", "", "/a/b/1", "/e",
+		"/d/0", "/a/b/01", "/a"}
+	exp := [][]byte{
+		[]byte(` "x"`), []byte(` null`), nil, doc, []byte(` {"c": null}`),
+		[]byte(` "x"`), nil, nil, []byte(` {"b": [1, {"c": null}]}`),
+	}
+	got, err := FindManyOrdered(doc, paths)
+	if err != nil {
+		t.Fatalf("Error finding many: %v", err)
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %q, got %q", exp, got)
+	}
+}
+
+func TestFindManyOrderedBroken(t *testing.T) {
+	got, err := FindManyOrdered([]byte(`{"a": 1, "b": [}`), []string{"/a", "/b/0"})
+	if err == nil {
+		t.Errorf("Expected error on broken JSON, got %q", got)
+	}
+	if !reflect.DeepEqual(got, [][]byte{nil, nil}) {
+		t.Errorf("Expected no values with the error, got %q", got)
+	}
+
+	for _, doc := range []string{`{} x`, `[]]`, `{"a": [}`, ` `, `1 2`} {
+		got, err := FindManyOrdered([]byte(doc), []string{""})
+		if err == nil || got[0] != nil {
+			t.Errorf("Expected error finding the root of %q, got %q, %v", doc, got, err)
+		}
+	}
+	for _, doc := range []string{` {"a": [1]} `, `[]`, `"x"`} {
+		got, err := FindManyOrdered([]byte(doc), []string{""})
+		if err != nil || string(got[0]) != doc {
+			t.Errorf("Expected to find the root of %q, got %q, %v", doc, got, err)
+		}
+	}
+}
+
+func TestFindManyMatchesFind(t *testing.T) {
+	doc := []byte(objSrc)
+	pointers, err := ListPointers(doc)
+	if err != nil {
+		t.Fatalf("Error listing pointers: %v", err)
+	}
+	got, err := FindManyOrdered(doc, pointers)
+	if err != nil {
+		t.Fatalf("Error finding many: %v", err)
+	}
+	for i, p := range pointers {
+		exp, err := Find(doc, p)
+		if err != nil {
+			t.Fatalf("Error finding %v: %v", p, err)
+		}
+		if p != "" && string(got[i]) != string(exp) {
+			t.Errorf("On %v, expected %s, got %s", p, exp, got[i])
+		}
+	}
+}
+
+func BenchmarkFindManyLarge(b *testing.B) {
+	var paths []string
+	for i := 0; i < 50; i++ {
+		paths = append(paths, "/tree/kids/0/kids/"+strconv.Itoa(i%5)+
+			"/kids/"+strconv.Itoa(i/5)+"/name")
+	}
+	b.SetBytes(int64(len(codeJSON)))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := FindManyOrdered(codeJSON, paths); err != nil {
+			b.Fatalf("Error finding many: %v", err)
+		}
+	}
+}
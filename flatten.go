@@ -0,0 +1,168 @@
+package jsonpointer
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/dustin/gojson"
+)
+
+// Flatten maps the pointer of every leaf in a document, that is every
+// scalar and empty object or array, to its raw JSON.  Where an object
+// has a key more than once, the first member is used.
+func Flatten(data []byte) (map[string][]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("Invalid JSON")
+	}
+	w := newWalker(data)
+	w.trackDuplicates(DuplicateFirst)
+
+	rv := map[string][]byte{}
+	// starts holds where each open container began, and empty
+	// whether it has had a value yet.
+	var starts []int
+	var empty []bool
+	// value is set when the next thing to begin is a value rather
+	// than a key.
+	value := true
+	for !w.done() {
+		newOp, err := w.step()
+		if err != nil {
+			return nil, err
+		}
+
+		isValue := false
+		switch newOp {
+		case json.ScanBeginLiteral, json.ScanBeginObject, json.ScanBeginArray:
+			isValue = value
+			if isValue && len(empty) > 0 {
+				empty[len(empty)-1] = false
+			}
+			value = newOp == json.ScanBeginArray
+		case json.ScanObjectKey, json.ScanArrayValue:
+			value = true
+		case json.ScanObjectValue, json.ScanEndObject, json.ScanEndArray:
+			value = false
+		}
+
+		switch newOp {
+		case json.ScanBeginArray, json.ScanBeginObject:
+			starts = append(starts, w.offset-1)
+			empty = append(empty, true)
+		case json.ScanBeginLiteral:
+			if isValue && !w.suppressed() {
+				rv[w.pointer()] = data[w.offset-1 : w.offset-1+valueEnd(data[w.offset-1:])]
+			}
+		case json.ScanEndArray, json.ScanEndObject:
+			depth := len(starts)
+			if empty[depth-1] && !w.suppressed() {
+				rv[w.pointer()] = data[starts[depth-1]:w.offset]
+			}
+			starts = starts[:depth-1]
+			empty = empty[:depth-1]
+		}
+	}
+	if err := w.finish(); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+// UnflattenOptions controls how documents are rebuilt.
+type UnflattenOptions struct {
+	// ForceObjects builds objects everywhere, rather than arrays
+	// where the keys are exactly the array indices from 0.
+	ForceObjects bool
+}
+
+// Unflatten rebuilds a document from a map of pointers to raw JSON,
+// as Flatten produces.  Objects whose keys are exactly the array
+// indices from 0 are rebuilt as arrays, and members are written in
+// the order Sort gives.  Pointers within another's value are reported
+// as a PointerError wrapping ErrConflict.
+func Unflatten(m map[string][]byte) ([]byte, error) {
+	return UnflattenWith(m, UnflattenOptions{})
+}
+
+// UnflattenWith is Unflatten with options.
+func UnflattenWith(m map[string][]byte, opts UnflattenOptions) ([]byte, error) {
+	paths := make([]string, 0, len(m))
+	for p := range m {
+		paths = append(paths, p)
+	}
+	Sort(paths)
+
+	root := &docNode{}
+	for _, p := range paths {
+		raw := m[p]
+		if !validValue(raw) {
+			return nil, &PointerError{p, fmt.Errorf("invalid JSON value %q", raw)}
+		}
+		n, err := root.place(SplitLenient(p), noArrays)
+		if err != nil {
+			return nil, &PointerError{p, err}
+		}
+		n.raw = raw
+	}
+
+	var b bytes.Buffer
+	root.write(&b, !opts.ForceObjects)
+	return b.Bytes(), nil
+}
+
+func noArrays(int) bool {
+	return false
+}
+
+// FlattenMap is Flatten for a decoded document made of
+// map[string]interface{} and []interface{} values.
+func FlattenMap(v interface{}) map[string]interface{} {
+	rv := map[string]interface{}{}
+	flattenMap(v, "", rv)
+	return rv
+}
+
+func flattenMap(v interface{}, prefix string, rv map[string]interface{}) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if len(x) > 0 {
+			for k, c := range x {
+				flattenMap(c, prefix+"/"+EscapeToken(k), rv)
+			}
+			return
+		}
+	case []interface{}:
+		if len(x) > 0 {
+			for i, c := range x {
+				flattenMap(c, prefix+"/"+strconv.Itoa(i), rv)
+			}
+			return
+		}
+	}
+	rv[prefix] = v
+}
+
+// UnflattenMap is Unflatten producing a decoded document.
+func UnflattenMap(m map[string]interface{}) (interface{}, error) {
+	return UnflattenMapWith(m, UnflattenOptions{})
+}
+
+// UnflattenMapWith is UnflattenMap with options.
+func UnflattenMapWith(m map[string]interface{}, opts UnflattenOptions) (interface{}, error) {
+	paths := make([]string, 0, len(m))
+	for p := range m {
+		paths = append(paths, p)
+	}
+	Sort(paths)
+
+	root := &docNode{}
+	for _, p := range paths {
+		n, err := root.place(SplitLenient(p), noArrays)
+		if err != nil {
+			return nil, &PointerError{p, err}
+		}
+		n.value = m[p]
+	}
+	return root.build(!opts.ForceObjects), nil
+}
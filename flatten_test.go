@@ -0,0 +1,137 @@
+package jsonpointer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dustin/gojson"
+)
+
+func TestFlatten(t *testing.T) {
+	doc := `{"a": {"b": [1, "x", {}], "c": []}, "d/e": null, "a": 9, "f": [[true]]}`
+	got, err := Flatten([]byte(doc))
+	if err != nil {
+		t.Fatalf("Error flattening: %v", err)
+	}
+	exp := map[string][]byte{
+		"/a/b/0": []byte("1"),
+		"/a/b/1": []byte(`"x"`),
+		"/a/b/2": []byte("{}"),
+		"/a/c":   []byte("[]"),
+		"/d~1e":  []byte("null"),
+		"/f/0/0": []byte("true"),
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %s, got %s", exp, got)
+	}
+
+	roots := map[string]string{"5": "5", " [ ] ": "[ ]", `"s"`: `"s"`}
+	for d, exp := range roots {
+		got, err := Flatten([]byte(d))
+		if err != nil || len(got) != 1 || string(got[""]) != exp {
+			t.Errorf("On %q, expected %q, got %s/%v", d, exp, got, err)
+		}
+	}
+	for _, d := range badDocs {
+		if got, err := Flatten(d); err == nil {
+			t.Errorf("Expected error flattening %q, got %s", d, got)
+		}
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	tests := []struct {
+		in    map[string][]byte
+		exp   string
+		force string
+	}{
+		{map[string][]byte{"/a/1": []byte("2"), "/a/0": []byte("1"),
+			"/b/x": []byte(`"y"`), "/c~1d": []byte("[]")},
+			`{"a":[1,2],"b":{"x":"y"},"c/d":[]}`,
+			`{"a":{"0":1,"1":2},"b":{"x":"y"},"c/d":[]}`},
+		{map[string][]byte{"/0": []byte("1"), "/2": []byte("3")},
+			`{"0":1,"2":3}`, `{"0":1,"2":3}`},
+		{map[string][]byte{"": []byte(" 5 ")}, " 5 ", " 5 "},
+		{map[string][]byte{}, "{}", "{}"},
+	}
+	for _, test := range tests {
+		got, err := Unflatten(test.in)
+		if err != nil || string(got) != test.exp {
+			t.Errorf("Expected %s, got %s/%v", test.exp, got, err)
+		}
+		got, err = UnflattenWith(test.in, UnflattenOptions{ForceObjects: true})
+		if err != nil || string(got) != test.force {
+			t.Errorf("Expected %s, got %s/%v", test.force, got, err)
+		}
+	}
+}
+
+func TestUnflattenErrors(t *testing.T) {
+	conflicts := []map[string][]byte{
+		{"/a": []byte("1"), "/a/b": []byte("2")},
+		{"": []byte("1"), "/a": []byte("2")},
+	}
+	for _, m := range conflicts {
+		if got, err := Unflatten(m); !errors.Is(err, ErrConflict) {
+			t.Errorf("Expected conflict unflattening %s, got %s/%v", m, got, err)
+		}
+	}
+	for _, raw := range []string{"", "[", "1 2", "nope"} {
+		if got, err := Unflatten(map[string][]byte{"/a": []byte(raw)}); err == nil {
+			t.Errorf("Expected error unflattening %q, got %s", raw, got)
+		}
+	}
+}
+
+func TestFlattenRoundTrip(t *testing.T) {
+	flat, err := Flatten(codeJSON)
+	if err != nil {
+		t.Fatalf("Error flattening: %v", err)
+	}
+	data, err := Unflatten(flat)
+	if err != nil {
+		t.Fatalf("Error unflattening: %v", err)
+	}
+	var exp, got interface{}
+	if err := json.Unmarshal(codeJSON, &exp); err != nil {
+		t.Fatalf("Error decoding: %v", err)
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Error decoding %s: %v", data, err)
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Round trip changed the document")
+	}
+}
+
+func TestFlattenMap(t *testing.T) {
+	var doc interface{}
+	err := json.Unmarshal([]byte(`{"a": [1, {"b": null}, []], "c/d": {}}`), &doc)
+	if err != nil {
+		t.Fatalf("Error decoding: %v", err)
+	}
+	flat := FlattenMap(doc)
+	exp := map[string]interface{}{
+		"/a/0": 1.0, "/a/1/b": nil, "/a/2": []interface{}{},
+		"/c~1d": map[string]interface{}{},
+	}
+	if !reflect.DeepEqual(flat, exp) {
+		t.Errorf("Expected %v, got %v", exp, flat)
+	}
+
+	got, err := UnflattenMap(flat)
+	if err != nil || !reflect.DeepEqual(got, doc) {
+		t.Errorf("Expected %v, got %v/%v", doc, got, err)
+	}
+	got, err = UnflattenMapWith(flat, UnflattenOptions{ForceObjects: true})
+	exp2 := map[string]interface{}{"a": map[string]interface{}{
+		"0": 1.0, "1": map[string]interface{}{"b": nil}, "2": []interface{}{}},
+		"c/d": map[string]interface{}{}}
+	if err != nil || !reflect.DeepEqual(got, exp2) {
+		t.Errorf("Expected %v, got %v/%v", exp2, got, err)
+	}
+	if _, err := UnflattenMap(map[string]interface{}{"/a": 1, "/a/0": 2}); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected conflict, got %v", err)
+	}
+}
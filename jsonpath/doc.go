@@ -0,0 +1,10 @@
+// Package jsonpath evaluates a practical subset of RFC9535 JSONPath
+// expressions over raw JSON, reporting each result as a normalized
+// path and as an RFC6901 JSON Pointer.
+//
+// Supported are the root identifier $, child segments (.name, .*,
+// ['name'], [0], [-1], [start:end:step], [*]), descendant segments
+// (..name, ..*, ..[...]) and filter selectors ([?@.price < 10],
+// [?@.isbn], [?!@.a && (@.b == 'x' || $.c)]) comparing singular
+// queries and literals.  Function extensions are not supported.
+package jsonpath
@@ -0,0 +1,475 @@
+package jsonpath
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/dustin/go-jsonpointer"
+	"github.com/dustin/gojson"
+)
+
+// Path is a parsed JSONPath expression.
+type Path struct {
+	expr string
+	q    query
+}
+
+// Parse parses a JSONPath expression.
+func Parse(expr string) (*Path, error) {
+	p := &parser{s: expr}
+	if !p.consume("$") {
+		return nil, p.errorf("expected '$'")
+	}
+	segs, err := p.segments()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q", p.s[p.pos:])
+	}
+	return &Path{expr, query{segments: segs}}, nil
+}
+
+// MustParse is like Parse, but panics on an invalid expression.
+func MustParse(expr string) *Path {
+	p, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the source text of the expression.
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Result is a value selected by a JSONPath expression.
+type Result struct {
+	// Path is the normalized path of the value, e.g. $['a'][0].
+	Path string
+	// Pointer is the RFC6901 JSON Pointer of the value, e.g. /a/0.
+	Pointer string
+	// Value is the raw JSON of the value.
+	Value []byte
+}
+
+// Query evaluates a JSONPath expression against raw JSON.
+func Query(data []byte, expr string) ([]Result, error) {
+	p, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Eval(data)
+}
+
+// Eval evaluates the expression against raw JSON, returning results
+// in the order RFC9535 prescribes.  The document is read once, to
+// validate and index it.
+func (p *Path) Eval(data []byte) ([]Result, error) {
+	d, err := index(data)
+	if err != nil {
+		return nil, err
+	}
+	nodes := d.evalQuery(p.q, 0)
+	rv := make([]Result, len(nodes))
+	for i, n := range nodes {
+		pointer, path := d.locate(n)
+		rv[i] = Result{path, pointer, d.raw(n)}
+	}
+	return rv, nil
+}
+
+// token is one step of a location, either an object member name or
+// an array index.
+type token struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// node is a value within a document.
+type node struct {
+	// start and end delimit the raw JSON of the value.
+	start, end int
+	// parent is the node enclosing this one, or -1 for the root.
+	parent int
+	// tok is the name or index of the value within its parent.
+	tok token
+	// kind is '{' or '[' for containers, and 0 for scalars.
+	kind byte
+	// kids holds the members of an object or the elements of an
+	// array, in document order.
+	kids []int
+	// pointer and path are the node's location, formatted when
+	// first needed; path is empty until then.
+	pointer, path string
+}
+
+// document is raw JSON indexed in one pass of the scanner, so that
+// evaluation needn't read any of it again.  The root is node 0.
+type document struct {
+	data  []byte
+	nodes []node
+}
+
+// index validates data as a single JSON value and indexes it.
+func index(data []byte) (*document, error) {
+	d := &document{data: data}
+	scan := &json.Scanner{}
+	scan.Reset()
+
+	// open holds the containers enclosing the scan, and scalar the
+	// scalar being read, or -1.
+	var open []int
+	scalar := -1
+	// inKey is set where an object key may begin, and keyStart is
+	// where the current one did.
+	inKey := false
+	keyStart := 0
+	var key string
+	for offset, c := range data {
+		switch op := scan.Step(scan, int(c)); op {
+		case json.ScanError:
+			return nil, fmt.Errorf("invalid JSON at offset %v", offset)
+		case json.ScanBeginLiteral, json.ScanBeginObject, json.ScanBeginArray:
+			if inKey {
+				keyStart = offset
+				break
+			}
+			n := d.add(open, key, offset)
+			if op == json.ScanBeginLiteral {
+				scalar = n
+			} else {
+				d.nodes[n].kind = c
+				open = append(open, n)
+				inKey = op == json.ScanBeginObject
+			}
+		case json.ScanObjectKey:
+			k, ok := json.UnquoteBytes(trimSpace(data[keyStart:offset]))
+			if !ok {
+				return nil, fmt.Errorf("invalid key %q", data[keyStart:offset])
+			}
+			key = string(k)
+			inKey = false
+		case json.ScanObjectValue, json.ScanArrayValue:
+			d.end(scalar, offset)
+			scalar = -1
+			inKey = op == json.ScanObjectValue
+		case json.ScanEndObject, json.ScanEndArray:
+			d.end(scalar, offset)
+			scalar = -1
+			d.nodes[open[len(open)-1]].end = offset + 1
+			open = open[:len(open)-1]
+			inKey = false
+		}
+	}
+	if scan.EOF() == json.ScanError {
+		// Either the input ended early, or the scanner noted
+		// garbage after the value in its last byte.
+		return nil, fmt.Errorf("invalid JSON at offset %v", len(data))
+	}
+	d.end(scalar, len(data))
+	return d, nil
+}
+
+// add adds a value beginning at offset within the innermost of the
+// open containers, as the member with the given key if that's an
+// object.
+func (d *document) add(open []int, key string, offset int) int {
+	n := node{start: offset, parent: -1}
+	if len(open) > 0 {
+		n.parent = open[len(open)-1]
+		p := &d.nodes[n.parent]
+		if p.kind == '[' {
+			n.tok = token{index: len(p.kids), isIndex: true}
+		} else {
+			n.tok = token{name: key}
+		}
+		p.kids = append(p.kids, len(d.nodes))
+	}
+	d.nodes = append(d.nodes, n)
+	return len(d.nodes) - 1
+}
+
+// end notes that the scalar n, if any, ends before the whitespace
+// preceding offset.
+func (d *document) end(n, offset int) {
+	if n < 0 {
+		return
+	}
+	for offset > d.nodes[n].start && isSpace(d.data[offset-1]) {
+		offset--
+	}
+	d.nodes[n].end = offset
+}
+
+// raw returns the raw JSON of node n.
+func (d *document) raw(n int) []byte {
+	return d.data[d.nodes[n].start:d.nodes[n].end]
+}
+
+// locate returns the RFC6901 JSON Pointer and the normalized path of
+// node n.  They're kept for any nodes within it to extend.
+func (d *document) locate(n int) (string, string) {
+	nd := &d.nodes[n]
+	if nd.path != "" {
+		return nd.pointer, nd.path
+	}
+	if nd.parent < 0 {
+		nd.path = "$"
+		return nd.pointer, nd.path
+	}
+	pointer, path := d.locate(nd.parent)
+	if nd.tok.isIndex {
+		i := strconv.Itoa(nd.tok.index)
+		nd.pointer = pointer + "/" + i
+		nd.path = path + "[" + i + "]"
+	} else {
+		nd.pointer = pointer + "/" + jsonpointer.EscapeToken(nd.tok.name)
+		nd.path = path + "['" + escapeName(nd.tok.name) + "']"
+	}
+	return nd.pointer, nd.path
+}
+
+// escapeName escapes a member name for a normalized path.
+func escapeName(name string) string {
+	var b []byte
+	for _, r := range name {
+		switch {
+		case r == '\'' || r == '\\':
+			b = append(b, '\\', byte(r))
+		case r == '\b':
+			b = append(b, '\\', 'b')
+		case r == '\f':
+			b = append(b, '\\', 'f')
+		case r == '\n':
+			b = append(b, '\\', 'n')
+		case r == '\r':
+			b = append(b, '\\', 'r')
+		case r == '\t':
+			b = append(b, '\\', 't')
+		case r < 0x20:
+			b = append(b, fmt.Sprintf(`\u%04x`, r)...)
+		default:
+			b = append(b, string(r)...)
+		}
+	}
+	return string(b)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func trimSpace(b []byte) []byte {
+	for len(b) > 0 && isSpace(b[0]) {
+		b = b[1:]
+	}
+	for len(b) > 0 && isSpace(b[len(b)-1]) {
+		b = b[:len(b)-1]
+	}
+	return b
+}
+
+func (d *document) evalQuery(q query, current int) []int {
+	nodes := []int{0}
+	if q.relative {
+		nodes = []int{current}
+	}
+	for _, seg := range q.segments {
+		var next []int
+		for _, n := range nodes {
+			next = d.evalSegment(seg, n, next)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// evalSegment appends the nodes seg selects from n to rv.
+func (d *document) evalSegment(seg segment, n int, rv []int) []int {
+	for _, sel := range seg.selectors {
+		rv = d.evalSelector(sel, n, rv)
+	}
+	if seg.descendant {
+		for _, k := range d.nodes[n].kids {
+			rv = d.evalSegment(seg, k, rv)
+		}
+	}
+	return rv
+}
+
+func (d *document) evalSelector(sel selector, n int, rv []int) []int {
+	kids := d.nodes[n].kids
+	isArray := d.nodes[n].kind == '['
+	switch sel.kind {
+	case nameSelector:
+		if d.nodes[n].kind == '{' {
+			for _, k := range kids {
+				if d.nodes[k].tok.name == sel.name {
+					rv = append(rv, k)
+					break
+				}
+			}
+		}
+	case wildcardSelector:
+		rv = append(rv, kids...)
+	case indexSelector:
+		if i := sel.index; isArray {
+			if i < 0 {
+				i += len(kids)
+			}
+			if i >= 0 && i < len(kids) {
+				rv = append(rv, kids[i])
+			}
+		}
+	case sliceSelector:
+		if isArray {
+			for _, i := range sliceIndexes(sel, len(kids)) {
+				rv = append(rv, kids[i])
+			}
+		}
+	case filterSelector:
+		for _, k := range kids {
+			if d.evalLogical(sel.filter, k) {
+				rv = append(rv, k)
+			}
+		}
+	}
+	return rv
+}
+
+// sliceIndexes computes the indexes a slice selects from an array of
+// the given length, as RFC9535 section 2.3.4.2.2 describes.
+func sliceIndexes(sel selector, length int) []int {
+	step := 1
+	if sel.hasStep {
+		step = sel.step
+	}
+	if step == 0 {
+		return nil
+	}
+	normalize := func(i int) int {
+		if i < 0 {
+			return length + i
+		}
+		return i
+	}
+	clamp := func(i, lo, hi int) int {
+		if i < lo {
+			return lo
+		}
+		if i > hi {
+			return hi
+		}
+		return i
+	}
+
+	var rv []int
+	if step > 0 {
+		start, end := 0, length
+		if sel.hasStart {
+			start = clamp(normalize(sel.start), 0, length)
+		}
+		if sel.hasEnd {
+			end = clamp(normalize(sel.end), 0, length)
+		}
+		for i := start; i < end; i += step {
+			rv = append(rv, i)
+		}
+	} else {
+		start, end := length-1, -1
+		if sel.hasStart {
+			start = clamp(normalize(sel.start), -1, length-1)
+		}
+		if sel.hasEnd {
+			end = clamp(normalize(sel.end), -1, length-1)
+		}
+		for i := start; i > end; i += step {
+			rv = append(rv, i)
+		}
+	}
+	return rv
+}
+
+func (d *document) evalLogical(e logical, current int) bool {
+	switch e := e.(type) {
+	case orExpr:
+		for _, x := range e {
+			if d.evalLogical(x, current) {
+				return true
+			}
+		}
+		return false
+	case andExpr:
+		for _, x := range e {
+			if !d.evalLogical(x, current) {
+				return false
+			}
+		}
+		return true
+	case notExpr:
+		return !d.evalLogical(e.e, current)
+	case existExpr:
+		return len(d.evalQuery(e.q, current)) > 0
+	case compareExpr:
+		return d.evalCompare(e, current)
+	}
+	panic(fmt.Sprintf("unhandled filter expression %T", e))
+}
+
+// nothing is the value of a query that selects no single node.
+type nothing struct{}
+
+func (d *document) evalOperand(o operand, current int) interface{} {
+	if !o.isQuery {
+		return o.literal
+	}
+	nodes := d.evalQuery(o.q, current)
+	if len(nodes) != 1 {
+		return nothing{}
+	}
+	var v interface{}
+	if err := json.Unmarshal(d.raw(nodes[0]), &v); err != nil {
+		// The document has been validated already.
+		panic(err)
+	}
+	return v
+}
+
+func (d *document) evalCompare(e compareExpr, current int) bool {
+	left := d.evalOperand(e.left, current)
+	right := d.evalOperand(e.right, current)
+
+	switch e.op {
+	case "==":
+		return reflect.DeepEqual(left, right)
+	case "!=":
+		return !reflect.DeepEqual(left, right)
+	case "<":
+		return less(left, right)
+	case ">":
+		return less(right, left)
+	case "<=":
+		return less(left, right) || reflect.DeepEqual(left, right)
+	case ">=":
+		return less(right, left) || reflect.DeepEqual(left, right)
+	}
+	panic("unhandled comparison " + e.op)
+}
+
+// less orders numbers and strings; no other values are ordered.
+func less(a, b interface{}) bool {
+	switch a := a.(type) {
+	case float64:
+		b, ok := b.(float64)
+		return ok && a < b
+	case string:
+		b, ok := b.(string)
+		return ok && a < b
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package jsonpath
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The example document from RFC9535 section 1.5.
+const store = `{ "store": {
+    "book": [
+      { "category": "reference",
+        "author": "Nigel Rees",
+        "title": "Sayings of the Century",
+        "price": 8.95
+      },
+      { "category": "fiction",
+        "author": "Evelyn Waugh",
+        "title": "Sword of Honour",
+        "price": 12.99
+      },
+      { "category": "fiction",
+        "author": "Herman Melville",
+        "title": "Moby Dick",
+        "isbn": "0-553-21311-3",
+        "price": 8.99
+      },
+      { "category": "fiction",
+        "author": "J. R. R. Tolkien",
+        "title": "The Lord of the Rings",
+        "isbn": "0-395-19395-8",
+        "price": 22.99
+      }
+    ],
+    "bicycle": {
+      "color": "red",
+      "price": 399
+    }
+  }
+}`
+
+func pointers(t *testing.T, expr string) []string {
+	results, err := Query([]byte(store), expr)
+	if err != nil {
+		t.Fatalf("Error evaluating %v: %v", expr, err)
+	}
+	var rv []string
+	for _, r := range results {
+		rv = append(rv, r.Pointer)
+	}
+	return rv
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		expr string
+		exp  []string
+	}{
+		{"$", []string{""}},
+		{"$.store.book[*].author", []string{"/store/book/0/author",
+			"/store/book/1/author", "/store/book/2/author",
+			"/store/book/3/author"}},
+		{"$..author", []string{"/store/book/0/author",
+			"/store/book/1/author", "/store/book/2/author",
+			"/store/book/3/author"}},
+		{"$.store.*", []string{"/store/book", "/store/bicycle"}},
+		{"$.store..price", []string{"/store/book/0/price",
+			"/store/book/1/price", "/store/book/2/price",
+			"/store/book/3/price", "/store/bicycle/price"}},
+		{"$..book[2]", []string{"/store/book/2"}},
+		{"$..book[-1]", []string{"/store/book/3"}},
+		{"$..book[0,1]", []string{"/store/book/0", "/store/book/1"}},
+		{"$..book[:2]", []string{"/store/book/0", "/store/book/1"}},
+		{"$..book[::-2]", []string{"/store/book/3", "/store/book/1"}},
+		{"$..book[1:3]", []string{"/store/book/1", "/store/book/2"}},
+		{"$..book[?@.isbn]", []string{"/store/book/2", "/store/book/3"}},
+		{"$..book[?!@.isbn]", []string{"/store/book/0", "/store/book/1"}},
+		{"$..book[?@.price<10]", []string{"/store/book/0", "/store/book/2"}},
+		{"$..book[?(@.price >= 12.99 && @.category == 'fiction')].title",
+			[]string{"/store/book/1/title", "/store/book/3/title"}},
+		{`$..book[?@.author == "Nigel Rees" || @.price > 20]`,
+			[]string{"/store/book/0", "/store/book/3"}},
+		{"$..book[?@.price < $.store.bicycle.price][?@ == 'Moby Dick']",
+			[]string{"/store/book/2/title"}},
+		{"$.store.book[?@.price < $.store.bicycle.price].isbn",
+			[]string{"/store/book/2/isbn", "/store/book/3/isbn"}},
+		{"$['store']['bicycle'].color", []string{"/store/bicycle/color"}},
+		{"$.store.nope", nil},
+		{"$.store.book.author", nil},
+		{"$.store.book[9]", nil},
+		{"$.store.bicycle[0]", nil},
+	}
+
+	for _, test := range tests {
+		got := pointers(t, test.expr)
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("On %v, expected %#v, got %#v", test.expr, test.exp, got)
+		}
+	}
+}
+
+func TestQueryResults(t *testing.T) {
+	results, err := Query([]byte(`{"a/b": {"it's": [1, "x"]}}`), "$..[1]")
+	if err != nil {
+		t.Fatalf("Error evaluating: %v", err)
+	}
+	exp := []Result{{`$['a/b']['it\'s'][1]`, "/a~1b/it's/1", []byte(`"x"`)}}
+	if !reflect.DeepEqual(results, exp) {
+		t.Errorf("Expected %#v, got %#v", exp, results)
+	}
+
+	results, err = Query([]byte(` { "a" : [ 1 , {} ] , "b":true }`), "$.*")
+	if err != nil {
+		t.Fatalf("Error evaluating: %v", err)
+	}
+	exp = []Result{{"$['a']", "/a", []byte(`[ 1 , {} ]`)},
+		{"$['b']", "/b", []byte(`true`)}}
+	if !reflect.DeepEqual(results, exp) {
+		t.Errorf("Expected %#v, got %#v", exp, results)
+	}
+}
+
+func TestQueryDescendantOrder(t *testing.T) {
+	got := []string{}
+	results, err := Query([]byte(`{"o": {"j": 1, "k": 2}, "a": [5, 3, [{"j": 4}, {"k": 6}]]}`), "$..j")
+	if err != nil {
+		t.Fatalf("Error evaluating: %v", err)
+	}
+	for _, r := range results {
+		got = append(got, r.Path)
+	}
+	exp := []string{"$['o']['j']", "$['a'][2][0]['j']"}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %#v, got %#v", exp, got)
+	}
+}
+
+func TestQueryBrokenJSON(t *testing.T) {
+	tests := []struct {
+		doc, expr string
+	}{
+		{`{"a": [1, }`, "$.a[0]"},
+		{`{"a":1, "b":`, "$.a"},
+		{`{"a":1}}`, "$.a"},
+		{`{"a":1} x`, "$.a"},
+		{`{"a":1}{}`, "$"},
+		{`{"a":1`, "$"},
+		{``, "$"},
+	}
+	for _, test := range tests {
+		got, err := Query([]byte(test.doc), test.expr)
+		if err == nil {
+			t.Errorf("Expected error on broken JSON %q with %v, got %v",
+				test.doc, test.expr, got)
+		}
+	}
+}
+
+// deep is an array nested 2000 deep.
+var deep = []byte(strings.Repeat("[", 2000) + strings.Repeat("]", 2000))
+
+func TestQueryDeep(t *testing.T) {
+	results, err := Query(deep, "$..*")
+	if err != nil {
+		t.Fatalf("Error evaluating: %v", err)
+	}
+	if len(results) != 1999 {
+		t.Fatalf("Expected 1999 results, got %v", len(results))
+	}
+	last := results[len(results)-1]
+	if exp := strings.Repeat("/0", 1999); last.Pointer != exp || string(last.Value) != "[]" {
+		t.Errorf("Expected [] at %v, got %s at %v", exp, last.Value, last.Pointer)
+	}
+}
+
+func BenchmarkQueryDeep(b *testing.B) {
+	p := MustParse("$..*")
+	for i := 0; i < b.N; i++ {
+		if _, err := p.Eval(deep); err != nil {
+			b.Fatalf("Error evaluating: %v", err)
+		}
+	}
+}
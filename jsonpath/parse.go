@@ -0,0 +1,454 @@
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type selectorKind int
+
+const (
+	nameSelector selectorKind = iota
+	wildcardSelector
+	indexSelector
+	sliceSelector
+	filterSelector
+)
+
+type selector struct {
+	kind  selectorKind
+	name  string
+	index int
+	// slice bounds, each only meaningful if its has flag is set
+	start, end, step          int
+	hasStart, hasEnd, hasStep bool
+	filter                    logical
+}
+
+type segment struct {
+	descendant bool
+	selectors  []selector
+}
+
+// query is a sequence of segments applied to the root ($) or the
+// current node (@).
+type query struct {
+	relative bool
+	segments []segment
+}
+
+// logical is a filter expression.
+type logical interface{}
+
+type orExpr []logical
+type andExpr []logical
+type notExpr struct{ e logical }
+type existExpr struct{ q query }
+type compareExpr struct {
+	op          string
+	left, right operand
+}
+
+// operand is either a literal or a singular query.
+type operand struct {
+	isQuery bool
+	q       query
+	literal interface{}
+}
+
+// SyntaxError describes a malformed JSONPath expression.
+type SyntaxError struct {
+	Expr   string
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid JSONPath %q at offset %v: %v", e.Expr, e.Offset, e.Msg)
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{p.s, p.pos, fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *parser) consume(tok string) bool {
+	if strings.HasPrefix(p.s[p.pos:], tok) {
+		p.pos += len(tok)
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(tok string) error {
+	p.skipSpace()
+	if !p.consume(tok) {
+		return p.errorf("expected %q", tok)
+	}
+	return nil
+}
+
+// segments parses segments until none follow.
+func (p *parser) segments() ([]segment, error) {
+	var rv []segment
+	for {
+		save := p.pos
+		p.skipSpace()
+		var seg segment
+		var err error
+		switch {
+		case p.consume(".."):
+			seg.descendant = true
+			if p.peek() == '[' {
+				p.pos++
+				seg.selectors, err = p.bracketed()
+			} else {
+				seg.selectors, err = p.shorthand()
+			}
+		case p.consume("."):
+			seg.selectors, err = p.shorthand()
+		case p.consume("["):
+			seg.selectors, err = p.bracketed()
+		default:
+			p.pos = save
+			return rv, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, seg)
+	}
+}
+
+// shorthand parses the * or member name following a dot.
+func (p *parser) shorthand() ([]selector, error) {
+	if p.consume("*") {
+		return []selector{{kind: wildcardSelector}}, nil
+	}
+	start := p.pos
+	for !p.eof() {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		if r == '_' || r >= 0x80 || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' ||
+			p.pos > start && '0' <= r && r <= '9' {
+			p.pos += size
+			continue
+		}
+		break
+	}
+	if p.pos == start {
+		return nil, p.errorf("expected member name or *")
+	}
+	return []selector{{kind: nameSelector, name: p.s[start:p.pos]}}, nil
+}
+
+// bracketed parses the selectors following a [.
+func (p *parser) bracketed() ([]selector, error) {
+	var rv []selector
+	for {
+		p.skipSpace()
+		sel, err := p.selector()
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, sel)
+		p.skipSpace()
+		if p.consume("]") {
+			return rv, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+func (p *parser) selector() (selector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		s, err := p.stringLiteral()
+		return selector{kind: nameSelector, name: s}, err
+	case c == '*':
+		p.pos++
+		return selector{kind: wildcardSelector}, nil
+	case c == '?':
+		p.pos++
+		e, err := p.or()
+		return selector{kind: filterSelector, filter: e}, err
+	}
+
+	var sel selector
+	var ok bool
+	var err error
+	sel.start, ok, err = p.optionalInt()
+	if err != nil {
+		return sel, err
+	}
+	p.skipSpace()
+	if p.peek() != ':' {
+		if !ok {
+			return sel, p.errorf("expected selector")
+		}
+		return selector{kind: indexSelector, index: sel.start}, nil
+	}
+	sel.kind = sliceSelector
+	sel.hasStart = ok
+	p.pos++
+	p.skipSpace()
+	if sel.end, sel.hasEnd, err = p.optionalInt(); err != nil {
+		return sel, err
+	}
+	p.skipSpace()
+	if p.consume(":") {
+		p.skipSpace()
+		if sel.step, sel.hasStep, err = p.optionalInt(); err != nil {
+			return sel, err
+		}
+	}
+	return sel, nil
+}
+
+// maxInt is the largest integer I-JSON represents exactly.
+const maxInt = 1<<53 - 1
+
+// optionalInt parses an integer if there is one.  As RFC9535 section
+// 2.3.3.1 defines them, integers have no leading zeros and no -0, and
+// are in the range I-JSON represents exactly.
+func (p *parser) optionalInt() (int, bool, error) {
+	start := p.pos
+	p.consume("-")
+	digits := p.pos
+	for !p.eof() && '0' <= p.peek() && p.peek() <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, false, nil
+	}
+	n, err := strconv.ParseInt(p.s[start:p.pos], 10, 64)
+	if err != nil || n > maxInt || n < -maxInt ||
+		p.s[digits] == '0' && p.pos-start > 1 {
+		p.pos = start
+		return 0, false, p.errorf("invalid integer")
+	}
+	return int(n), true, nil
+}
+
+// stringLiteral parses a single or double quoted string.
+func (p *parser) stringLiteral() (string, error) {
+	quote := p.peek()
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated string")
+			}
+			e := p.s[p.pos]
+			p.pos++
+			switch e {
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '/', '\\', '\'', '"':
+				b.WriteByte(e)
+			case 'u':
+				if p.pos+4 > len(p.s) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.s[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				p.pos += 4
+				b.WriteRune(rune(r))
+			default:
+				return "", p.errorf("invalid escape \\%c", e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) or() (logical, error) {
+	var rv orExpr
+	for {
+		e, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, e)
+		p.skipSpace()
+		if !p.consume("||") {
+			break
+		}
+	}
+	if len(rv) == 1 {
+		return rv[0], nil
+	}
+	return rv, nil
+}
+
+func (p *parser) and() (logical, error) {
+	var rv andExpr
+	for {
+		e, err := p.basic()
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, e)
+		p.skipSpace()
+		if !p.consume("&&") {
+			break
+		}
+	}
+	if len(rv) == 1 {
+		return rv[0], nil
+	}
+	return rv, nil
+}
+
+func (p *parser) basic() (logical, error) {
+	p.skipSpace()
+	if p.consume("!") {
+		p.skipSpace()
+		if p.peek() == '(' {
+			e, err := p.paren()
+			return notExpr{e}, err
+		}
+		q, err := p.query()
+		return notExpr{existExpr{q}}, err
+	}
+	if p.peek() == '(' {
+		return p.paren()
+	}
+
+	start := p.pos
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			if left.isQuery && !left.q.singular() {
+				p.pos = start
+				return nil, p.errorf("non-singular query in comparison")
+			}
+			p.skipSpace()
+			start = p.pos
+			right, err := p.operand()
+			if err == nil && right.isQuery && !right.q.singular() {
+				p.pos = start
+				err = p.errorf("non-singular query in comparison")
+			}
+			return compareExpr{op, left, right}, err
+		}
+	}
+	if !left.isQuery {
+		return nil, p.errorf("expected comparison operator")
+	}
+	return existExpr{left.q}, nil
+}
+
+func (p *parser) paren() (logical, error) {
+	p.pos++
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	return e, p.expect(")")
+}
+
+func (p *parser) query() (query, error) {
+	var q query
+	switch p.peek() {
+	case '@':
+		q.relative = true
+	case '$':
+	default:
+		return q, p.errorf("expected '@' or '$'")
+	}
+	p.pos++
+	var err error
+	q.segments, err = p.segments()
+	return q, err
+}
+
+// singular reports whether q is a singular query, selecting at most
+// one node, as comparisons require: each segment is a child segment
+// with a single name or index selector.
+func (q query) singular() bool {
+	for _, seg := range q.segments {
+		if seg.descendant || len(seg.selectors) != 1 {
+			return false
+		}
+		if k := seg.selectors[0].kind; k != nameSelector && k != indexSelector {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *parser) operand() (operand, error) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		q, err := p.query()
+		return operand{isQuery: true, q: q}, err
+	case c == '\'' || c == '"':
+		s, err := p.stringLiteral()
+		return operand{literal: s}, err
+	case c == '-' || '0' <= c && c <= '9':
+		start := p.pos
+		for !p.eof() && strings.IndexByte("+-.eE0123456789", p.peek()) >= 0 {
+			p.pos++
+		}
+		f, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+		if err != nil {
+			p.pos = start
+			return operand{}, p.errorf("invalid number")
+		}
+		return operand{literal: f}, nil
+	case p.consume("true"):
+		return operand{literal: true}, nil
+	case p.consume("false"):
+		return operand{literal: false}, nil
+	case p.consume("null"):
+		return operand{literal: nil}, nil
+	}
+	return operand{}, p.errorf("expected query or literal")
+}
@@ -0,0 +1,52 @@
+package jsonpath
+
+import (
+	"testing"
+)
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		"", "store", "$.", "$[", "$[1", "$['a'", "$[?]", "$[?@.a ==]",
+		"$[?1]", "$[a]", "$..", "$.a b", `$['\q']`, "$[?(@.a]",
+		"$[99999999999999999999]", "$[01]", "$[-0]", "$[-]", "$[00:1]",
+		"$[0:1:-01]", "$[9007199254740992]", "$[?@.* == 1]",
+		"$[?1 == @..a]", "$[?@['a', 'b'] < 2]", "$[?@[0:1] == $.x]",
+	}
+	for _, test := range tests {
+		p, err := Parse(test)
+		if err == nil {
+			t.Errorf("Expected error parsing %q, got %#v", test, p)
+		} else if _, ok := err.(*SyntaxError); !ok {
+			t.Errorf("Expected a syntax error parsing %q, got %v", test, err)
+		}
+	}
+}
+
+func TestParseValid(t *testing.T) {
+	tests := []string{
+		"$", "$.a", "$.*", "$..a", "$..*", "$..[0]", "$['a', \"b\"]",
+		"$[0, -1, 1:2, ::-1, *]", "$[ 1 : 5 : 2 ]", "$.ünïcode",
+		"$[?@.a && !@.b || (@.c != null)]", "$[?@.a == true]",
+		"$[?$.x >= -1.5e3]", `$['é\n']`, "$ .a [0]", "$[0, -9007199254740991]",
+		"$[?@.*]", "$[?@..a]", "$[?@['a'][-1] == $[0].b]",
+	}
+	for _, test := range tests {
+		if _, err := Parse(test); err != nil {
+			t.Errorf("Error parsing %q: %v", test, err)
+		}
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	p := MustParse("$.a")
+	if p.String() != "$.a" {
+		t.Errorf("Expected $.a, got %v", p)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic on invalid expression")
+		}
+	}()
+	MustParse("nope")
+}
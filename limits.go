@@ -0,0 +1,64 @@
+package jsonpointer
+
+// Limits bounds the resources used reading untrusted input.  Zero
+// fields are unlimited.  Exceeding a limit is reported as a
+// LimitError.
+type Limits struct {
+	// MaxDepth is the deepest nesting of objects and arrays
+	// allowed.  This is checked even within containers a lookup
+	// skips.
+	MaxDepth int
+	// MaxSize is the longest input allowed, in bytes.
+	MaxSize int
+	// MaxPointers is the most pointers ListPointers may list.
+	MaxPointers int
+	// MaxKeyLength is the longest object key allowed, in bytes as
+	// it appears in the input.  Keys within containers a lookup
+	// skips aren't checked, since they're never read.
+	MaxKeyLength int
+}
+
+func (l *Limits) checkSize(data []byte) error {
+	if l.MaxSize > 0 && len(data) > l.MaxSize {
+		return &LimitError{"MaxSize", l.MaxSize, l.MaxSize}
+	}
+	return nil
+}
+
+// checkDepth checks the depth of a container beginning at offset.
+func (l *Limits) checkDepth(depth, offset int) error {
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return &LimitError{"MaxDepth", l.MaxDepth, offset}
+	}
+	return nil
+}
+
+// nesting returns how many more levels may nest within a container
+// at depth, or -1 if any number may.
+func (l *Limits) nesting(depth int) int {
+	if l.MaxDepth > 0 {
+		return l.MaxDepth - depth
+	}
+	return -1
+}
+
+// checkKey checks a raw, quoted object key beginning at offset.
+func (l *Limits) checkKey(raw []byte, offset int) error {
+	if l.MaxKeyLength <= 0 {
+		return nil
+	}
+	for isSpace(rune(raw[len(raw)-1])) {
+		raw = raw[:len(raw)-1]
+	}
+	if len(raw)-2 > l.MaxKeyLength {
+		return &LimitError{"MaxKeyLength", l.MaxKeyLength, offset}
+	}
+	return nil
+}
+
+func (l *Limits) checkPointers(n, offset int) error {
+	if l.MaxPointers > 0 && n > l.MaxPointers {
+		return &LimitError{"MaxPointers", l.MaxPointers, offset}
+	}
+	return nil
+}
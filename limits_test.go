@@ -0,0 +1,90 @@
+package jsonpointer
+
+import (
+	"errors"
+	"testing"
+)
+
+const limitSrc = `{"a": {"b": [1, {"c": 2}]}, "long key": [[[[3]]]], "d": 4}`
+
+func TestFindLimits(t *testing.T) {
+	tests := []struct {
+		path   string
+		limits Limits
+		limit  string
+		offset int
+	}{
+		{"/d", Limits{MaxSize: 10}, "MaxSize", 10},
+		{"/a/b/1/c", Limits{MaxDepth: 3}, "MaxDepth", 16},
+		// The deep array is skipped, but still counted.
+		{"/d", Limits{MaxDepth: 4}, "MaxDepth", 43},
+		{"/d", Limits{MaxKeyLength: 7}, "MaxKeyLength", 28},
+		{"/a/b/1/c", Limits{MaxDepth: 4, MaxKeyLength: 8, MaxSize: 100}, "", 0},
+	}
+
+	for _, test := range tests {
+		opts := FindOptions{Limits: test.limits}
+		got, err := FindWith([]byte(limitSrc), test.path, opts)
+		if test.limit == "" {
+			if err != nil || got == nil {
+				t.Errorf("Expected to find %v within %+v, got %q, %v",
+					test.path, test.limits, got, err)
+			}
+			continue
+		}
+		var le *LimitError
+		if !errors.Is(err, ErrLimitExceeded) || !errors.As(err, &le) {
+			t.Errorf("Expected LimitError finding %v within %+v, got %q, %v",
+				test.path, test.limits, got, err)
+			continue
+		}
+		if le.Limit != test.limit || le.Offset != test.offset {
+			t.Errorf("Expected %v at %v finding %v, got %v at %v",
+				test.limit, test.offset, test.path, le.Limit, le.Offset)
+		}
+
+		_, err = FindManyWith([]byte(limitSrc), []string{test.path}, opts)
+		if !errors.As(err, &le) || le.Limit != test.limit || le.Offset != test.offset {
+			t.Errorf("Expected %v at %v from FindMany of %v, got %v",
+				test.limit, test.offset, test.path, err)
+		}
+	}
+}
+
+func TestListPointersLimits(t *testing.T) {
+	tests := []struct {
+		opts   ListOptions
+		limit  string
+		offset int
+	}{
+		{ListOptions{Limits: Limits{MaxPointers: 4}}, "MaxPointers", 14},
+		{ListOptions{Limits: Limits{MaxDepth: 4}}, "MaxDepth", 43},
+		{ListOptions{Limits: Limits{MaxKeyLength: 7}}, "MaxKeyLength", 28},
+		{ListOptions{Limits: Limits{MaxSize: 10}}, "MaxSize", 10},
+		{ListOptions{Prefix: "/long key", Limits: Limits{MaxDepth: 4}}, "MaxDepth", 43},
+		{ListOptions{Prefix: "/a", Limits: Limits{MaxPointers: 2}}, "MaxPointers", 12},
+	}
+
+	for _, test := range tests {
+		got, err := ListPointersWith([]byte(limitSrc), test.opts)
+		var le *LimitError
+		if !errors.As(err, &le) || le.Limit != test.limit || le.Offset != test.offset {
+			t.Errorf("Expected %v at %v listing with %+v, got %v, %v",
+				test.limit, test.offset, test.opts, got, err)
+		}
+	}
+
+	opts := ListOptions{Limits: Limits{MaxPointers: 12, MaxDepth: 5, MaxKeyLength: 8}}
+	got, err := ListPointersWith([]byte(limitSrc), opts)
+	if err != nil || len(got) != 12 {
+		t.Errorf("Expected 12 pointers within limits, got %v, %v", got, err)
+	}
+}
+
+func TestLimitError(t *testing.T) {
+	err := &LimitError{"MaxDepth", 3, 16}
+	exp := "limit exceeded: MaxDepth of 3 at offset 16"
+	if err.Error() != exp {
+		t.Errorf("Expected %q, got %q", exp, err.Error())
+	}
+}
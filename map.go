@@ -1,38 +1,80 @@
 package jsonpointer
 
-import (
-	"strconv"
-	"strings"
-)
-
 // Get the value at the specified path.
-func Get(m map[string]interface{}, path string) interface{} {
+//
+// The root may be any decoded JSON value, not only an object.  Along
+// with map[string]interface{} and []interface{}, common concretely
+// typed containers such as map[string]string and
+// []map[string]interface{} are walked directly.  Array indexes must
+// be written as RFC6901 requires, so "01" and "+1" address nothing.
+func Get(m interface{}, path string) interface{} {
 	if path == "" {
 		return m
 	}
 
-	parts := strings.Split(path[1:], "/")
-	var rv interface{} = m
+	rv, _ := getPath(m, SplitLenient(path), nil)
+	return rv
+}
+
+// GetOK is like Get, but also reports whether a value exists at the
+// path, distinguishing an explicit JSON null from a missing value.
+func GetOK(m interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return m, true
+	}
+
+	return getPath(m, SplitLenient(path), nil)
+}
 
-	for _, p := range parts {
+// getPath walks the given tokens from rv.  If indices is non-nil, it
+// holds each token pre-converted to an array index (or -1).
+func getPath(rv interface{}, parts []string, indices []int) (interface{}, bool) {
+	for n, p := range parts {
+		var ok bool
 		switch v := rv.(type) {
 		case map[string]interface{}:
-			if strings.Contains(p, "~") {
-				p = strings.Replace(p, "~1", "/", -1)
-				p = strings.Replace(p, "~0", "~", -1)
-			}
-			rv = v[p]
+			rv, ok = v[p]
+		case map[string]string:
+			rv, ok = v[p]
+		case map[string][]interface{}:
+			rv, ok = v[p]
+		case map[string]map[string]interface{}:
+			rv, ok = v[p]
 		case []interface{}:
-			i, err := strconv.Atoi(p)
-			if err == nil && i < len(v) {
+			i := tokenIndex(parts, indices, n)
+			if ok = i >= 0 && i < len(v); ok {
+				rv = v[i]
+			}
+		case []map[string]interface{}:
+			i := tokenIndex(parts, indices, n)
+			if ok = i >= 0 && i < len(v); ok {
+				rv = v[i]
+			}
+		case []string:
+			i := tokenIndex(parts, indices, n)
+			if ok = i >= 0 && i < len(v); ok {
+				rv = v[i]
+			}
+		case []float64:
+			i := tokenIndex(parts, indices, n)
+			if ok = i >= 0 && i < len(v); ok {
 				rv = v[i]
-			} else {
-				return nil
 			}
-		default:
-			return nil
+		}
+		if !ok {
+			return nil, false
 		}
 	}
 
-	return rv
+	return rv, true
+}
+
+// tokenIndex returns token n as an array index, or -1 if it isn't
+// one, using the pre-converted indices when available.  Either way
+// indices follow arrayIndex's rules, as they do for raw lookups.
+func tokenIndex(parts []string, indices []int, n int) int {
+	if indices != nil {
+		return indices[n]
+	}
+	return arrayIndex(parts[n])
 }
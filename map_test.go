@@ -33,6 +33,8 @@ var tests = []struct {
 	{"/foo", []interface{}{"bar", "baz"}},
 	{"/foo/0", "bar"},
 	{"/foo/99", nil},
+	{"/foo/01", nil},
+	{"/foo/+1", nil},
 	{"/foo/0/3", nil},
 	{"/", 0.0},
 	{"/a~1b", 1.0},
@@ -175,3 +177,69 @@ func TestFindEmptyObjectPanic823(t *testing.T) {
 		}
 	}
 }
+
+func TestGetArbitraryRoots(t *testing.T) {
+	var arr interface{}
+	if err := json.Unmarshal([]byte(`[{"a": 1}, [true, null], "x"]`), &arr); err != nil {
+		t.Fatalf("Error parsing array: %v", err)
+	}
+
+	tests := []struct {
+		root interface{}
+		path string
+		exp  interface{}
+	}{
+		{arr, "/0/a", 1.0},
+		{arr, "/1/0", true},
+		{arr, "/1/1", nil},
+		{arr, "/2", "x"},
+		{arr, "/3", nil},
+		{arr, "/-1", nil},
+		{"scalar", "", "scalar"},
+		{"scalar", "/0", nil},
+		{3.0, "/x", nil},
+		{nil, "/x", nil},
+		{map[string]string{"a/b": "c"}, "/a~1b", "c"},
+		{map[string]string{"a": "c"}, "/a/0", nil},
+		{[]map[string]interface{}{{"a": "b"}}, "/0/a", "b"},
+		{[]string{"x", "y"}, "/1", "y"},
+		{[]float64{1, 2}, "/0", 1.0},
+		{map[string][]interface{}{"l": {"v"}}, "/l/0", "v"},
+		{map[string]map[string]interface{}{"o": {"k": "v"}}, "/o/k", "v"},
+		{map[string]interface{}{"s": []string{"z"}}, "/s/0", "z"},
+	}
+
+	for _, test := range tests {
+		got := Get(test.root, test.path)
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("On %#v %v, expected %#v, got %#v",
+				test.root, test.path, test.exp, got)
+		}
+	}
+}
+
+func TestGetOK(t *testing.T) {
+	m := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(`{"a": null, "b": [null]}`), &m); err != nil {
+		t.Fatalf("Error parsing: %v", err)
+	}
+
+	tests := []struct {
+		path  string
+		found bool
+	}{
+		{"", true},
+		{"/a", true},
+		{"/b/0", true},
+		{"/b/1", false},
+		{"/c", false},
+		{"/a/x", false},
+	}
+	for _, test := range tests {
+		v, ok := GetOK(m, test.path)
+		if ok != test.found {
+			t.Errorf("On %v, expected found=%v, got %v (%v)",
+				test.path, test.found, ok, v)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package jsonpointer
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/dustin/gojson"
+)
+
+// Marshal builds a JSON document by placing the value of each field of
+// the struct v (or that v points to) at the location its jsonpointer
+// tag names, creating the objects and arrays on the way:
+//
+//	type Payload struct {
+//		ID   string `jsonpointer:"/data/id"`
+//		Name string `jsonpointer:"/data/attributes/0/name,omitempty"`
+//	}
+//
+// Tags are read as by Unmarshal; nil pointers to nested structs are
+// skipped, and fields tagged omitempty are skipped when empty as
+// encoding/json defines it.  Tokens that are array indices create
+// arrays, with any gaps filled with null, and other tokens create
+// objects, whose members are written in field order.  Placements
+// that can't coexist, such as a value within another value, are
+// reported as a PointerError wrapping ErrConflict.
+func Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("jsonpointer: Marshal needs a struct, got %T", v)
+	}
+
+	var fields []taggedField
+	if err := collectTagged(rv, "", nil, false, &fields); err != nil {
+		return nil, err
+	}
+	root := &docNode{}
+	for _, f := range fields {
+		if f.omitEmpty && isEmptyValue(f.v) {
+			continue
+		}
+		raw, err := json.Marshal(f.v.Interface())
+		if err != nil {
+			return nil, &PointerError{f.pointer, err}
+		}
+		tokens := SplitLenient(f.pointer)
+		n, err := root.place(tokens, func(i int) bool {
+			return arrayIndex(tokens[i]) >= 0
+		})
+		if err != nil {
+			return nil, &PointerError{f.pointer, err}
+		}
+		n.raw = raw
+	}
+
+	var b bytes.Buffer
+	root.write(&b, false)
+	return b.Bytes(), nil
+}
+
+// isEmptyValue reports whether v is empty as encoding/json's
+// omitempty defines it.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// docNode is part of a document being assembled from values placed at
+// pointers.
+type docNode struct {
+	// isLeaf is set where a value is placed, either raw or decoded.
+	isLeaf   bool
+	raw      []byte
+	value    interface{}
+	isArray  bool
+	isObject bool
+	// keys lists the members of an object in the order created.
+	keys    []string
+	members map[string]*docNode
+	// elems holds the elements of an array, nil where unset.
+	elems []*docNode
+}
+
+// place returns a new leaf at the location tokens name below n,
+// creating containers on the way: an array where arrayAt reports the
+// container of tokens[i] is one, and an object otherwise.
+func (n *docNode) place(tokens []string, arrayAt func(i int) bool) (*docNode, error) {
+	for i, t := range tokens {
+		if n.isLeaf {
+			return nil, ErrConflict
+		}
+		if !n.isArray && !n.isObject {
+			n.isArray = arrayAt(i)
+			n.isObject = !n.isArray
+		}
+
+		if n.isArray {
+			idx := arrayIndex(t)
+			if idx < 0 {
+				return nil, ErrConflict
+			}
+			for len(n.elems) <= idx {
+				n.elems = append(n.elems, nil)
+			}
+			if n.elems[idx] == nil {
+				n.elems[idx] = &docNode{}
+			}
+			n = n.elems[idx]
+			continue
+		}
+		c := n.members[t]
+		if c == nil {
+			c = &docNode{}
+			if n.members == nil {
+				n.members = map[string]*docNode{}
+			}
+			n.members[t] = c
+			n.keys = append(n.keys, t)
+		}
+		n = c
+	}
+
+	if n.isLeaf || n.isArray || n.isObject {
+		return nil, ErrConflict
+	}
+	n.isLeaf = true
+	return n, nil
+}
+
+// asArray returns the members of an object whose keys are exactly the
+// array indices from 0, in order, or nil if it has other keys.
+func (n *docNode) asArray() []*docNode {
+	if !n.isObject || len(n.keys) == 0 {
+		return nil
+	}
+	rv := make([]*docNode, len(n.keys))
+	for _, k := range n.keys {
+		i := arrayIndex(k)
+		if i < 0 || i >= len(rv) {
+			return nil
+		}
+		rv[i] = n.members[k]
+	}
+	return rv
+}
+
+// write writes the raw document rooted at n, with an empty root
+// written as an empty object.  With inferArrays set, objects keyed by
+// array indices are written as arrays.
+func (n *docNode) write(b *bytes.Buffer, inferArrays bool) {
+	elems := n.elems
+	if inferArrays && n.isObject {
+		elems = n.asArray()
+	}
+	switch {
+	case n.isLeaf:
+		b.Write(n.raw)
+	case n.isArray || elems != nil:
+		b.WriteByte('[')
+		for i, e := range elems {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if e == nil {
+				b.WriteString("null")
+			} else {
+				e.write(b, inferArrays)
+			}
+		}
+		b.WriteByte(']')
+	default:
+		b.WriteByte('{')
+		for i, k := range n.keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			key, _ := json.Marshal(k)
+			b.Write(key)
+			b.WriteByte(':')
+			n.members[k].write(b, inferArrays)
+		}
+		b.WriteByte('}')
+	}
+}
+
+// build returns the decoded document rooted at n, as write would
+// write it.
+func (n *docNode) build(inferArrays bool) interface{} {
+	elems := n.elems
+	if inferArrays && n.isObject {
+		elems = n.asArray()
+	}
+	switch {
+	case n.isLeaf:
+		return n.value
+	case n.isArray || elems != nil:
+		rv := make([]interface{}, len(elems))
+		for i, e := range elems {
+			if e != nil {
+				rv[i] = e.build(inferArrays)
+			}
+		}
+		return rv
+	}
+	rv := make(map[string]interface{}, len(n.keys))
+	for _, k := range n.keys {
+		rv[k] = n.members[k].build(inferArrays)
+	}
+	return rv
+}
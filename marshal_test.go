@@ -0,0 +1,110 @@
+package jsonpointer
+
+import (
+	"errors"
+	"testing"
+)
+
+type marshalAttrs struct {
+	Name  string `jsonpointer:"/name"`
+	Color string `jsonpointer:"/color,omitempty"`
+}
+
+type marshalPayload struct {
+	Type  string        `jsonpointer:"/data/type"`
+	ID    int           `jsonpointer:"/data/id"`
+	Attrs *marshalAttrs `jsonpointer:"/data/attributes"`
+	Tags  []string      `jsonpointer:"/data/meta/2"`
+	Slash bool          `jsonpointer:"/data/a~1b"`
+	Skip  string        `jsonpointer:"-"`
+	Plain string
+}
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		in  interface{}
+		exp string
+	}{
+		{marshalPayload{Type: "widget", ID: 7, Attrs: &marshalAttrs{Name: "w"},
+			Tags: []string{"x"}, Skip: "no", Plain: "no"},
+			`{"data":{"type":"widget","id":7,"attributes":{"name":"w"},` +
+				`"meta":[null,null,["x"]],"a/b":false}}`},
+		{&marshalPayload{Type: "t"},
+			`{"data":{"type":"t","id":0,"meta":[null,null,null],"a/b":false}}`},
+		{struct {
+			A string `jsonpointer:"/0/x"`
+			B string `jsonpointer:"/0/y"`
+			C int    `jsonpointer:"/1"`
+		}{"a", "b", 3}, `[{"x":"a","y":"b"},3]`},
+		{struct {
+			All map[string]int `jsonpointer:""`
+		}{map[string]int{"a": 1}}, `{"a":1}`},
+		{struct{}{}, `{}`},
+	}
+	for _, test := range tests {
+		got, err := Marshal(test.in)
+		if err != nil {
+			t.Errorf("Error marshaling %#v: %v", test.in, err)
+			continue
+		}
+		if string(got) != test.exp {
+			t.Errorf("Expected %s, got %s", test.exp, got)
+		}
+	}
+}
+
+func TestMarshalConflicts(t *testing.T) {
+	tests := []struct {
+		in  interface{}
+		ptr string
+	}{
+		{struct {
+			A int `jsonpointer:"/a"`
+			B int `jsonpointer:"/a"`
+		}{}, "/a"},
+		{struct {
+			A int `jsonpointer:"/a"`
+			B int `jsonpointer:"/a/b"`
+		}{}, "/a/b"},
+		{struct {
+			A int `jsonpointer:"/a/b"`
+			B int `jsonpointer:"/a"`
+		}{}, "/a"},
+		{struct {
+			A int `jsonpointer:"/a/0"`
+			B int `jsonpointer:"/a/b"`
+		}{}, "/a/b"},
+	}
+	for _, test := range tests {
+		got, err := Marshal(test.in)
+		var perr *PointerError
+		if !errors.As(err, &perr) || !errors.Is(err, ErrConflict) || perr.Pointer != test.ptr {
+			t.Errorf("Expected conflict at %v, got %s/%v", test.ptr, got, err)
+		}
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	in := marshalPayload{Type: "widget", ID: 7,
+		Attrs: &marshalAttrs{"w", "red"}, Tags: []string{"x", "y"}, Slash: true}
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("Error marshaling: %v", err)
+	}
+	var out marshalPayload
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatalf("Error unmarshaling %s: %v", data, err)
+	}
+	if out.Type != in.Type || out.ID != in.ID || *out.Attrs != *in.Attrs ||
+		len(out.Tags) != 2 || out.Slash != in.Slash {
+		t.Errorf("Expected %#v, got %#v", in, out)
+	}
+}
+
+func TestMarshalInvalid(t *testing.T) {
+	for _, in := range []interface{}{nil, 1, (*marshalPayload)(nil)} {
+		if got, err := Marshal(in); err == nil {
+			t.Errorf("Expected error marshaling %#v, got %s", in, got)
+		}
+	}
+}
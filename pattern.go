@@ -0,0 +1,241 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/dustin/gojson"
+)
+
+// A pattern is a JSON Pointer in which the token "*" matches any one
+// key or index and the token "**" matches any number (including none)
+// of keys and indexes.  There is no way to match a literal "*" or
+// "**" key with a pattern.
+//
+// Matching tracks, for each location in a document, a sorted set of
+// states: the number of pattern tokens consumed by each alternative
+// way of reaching it.
+type pattern []string
+
+func parsePattern(s string) pattern {
+	if s == "" {
+		return nil
+	}
+	return pattern(SplitLenient(s))
+}
+
+// closure adds the states reachable by letting "**" match nothing.
+func (p pattern) closure(states []int) []int {
+	for i := 0; i < len(states); i++ {
+		s := states[i]
+		if s < len(p) && p[s] == "**" && (i+1 == len(states) || states[i+1] != s+1) {
+			states = append(states, 0)
+			copy(states[i+2:], states[i+1:])
+			states[i+1] = s + 1
+		}
+	}
+	return states
+}
+
+func (p pattern) start() []int {
+	return p.closure([]int{0})
+}
+
+// step computes the states of the child addressed by tok.
+func (p pattern) step(states []int, tok string) []int {
+	var rv []int
+	add := func(s int) {
+		if len(rv) == 0 || rv[len(rv)-1] != s {
+			rv = append(rv, s)
+		}
+	}
+	for _, s := range states {
+		if s == len(p) {
+			continue
+		}
+		switch p[s] {
+		case "**":
+			add(s)
+		case "*", tok:
+			add(s + 1)
+		}
+	}
+	return p.closure(rv)
+}
+
+func (p pattern) accepts(states []int) bool {
+	return len(states) > 0 && states[len(states)-1] == len(p)
+}
+
+// Match is a value found by FindAll.
+type Match struct {
+	Pointer string
+	Value   []byte
+}
+
+// FindAll finds every section of raw JSON matching a pointer pattern
+// in one pass through the input.  In patterns, the token "*" matches
+// any single key or index and "**" matches any depth, so "/users/*/name"
+// finds every user's name and "/**/id" finds every id.
+//
+// Matches are returned in document order.
+func FindAll(data []byte, pat string) ([]Match, error) {
+	spans, err := findAll(data, []pattern{parsePattern(pat)})
+	var rv []Match
+	for _, s := range spans {
+		rv = append(rv, Match{s.pointer, data[s.start:s.end]})
+	}
+	return rv, err
+}
+
+// span locates a value within a document.
+type span struct {
+	pointer    string
+	start, end int
+}
+
+// findAll finds the values matching any of the patterns in one pass,
+// in document order.  Values other than the root include the
+// whitespace before them.
+func findAll(data []byte, pats []pattern) ([]span, error) {
+	var rv []span
+
+	// cur holds the states of the current value for each pattern.
+	cur := make([][]int, len(pats))
+	for i, p := range pats {
+		cur[i] = p.start()
+	}
+	accepts := func() bool {
+		for i, p := range pats {
+			if len(cur) > 0 && p.accepts(cur[i]) {
+				return true
+			}
+		}
+		return false
+	}
+	if accepts() {
+		rv = append(rv, span{"", 0, len(data)})
+	}
+
+	w := newWalker(data)
+	// levels holds the states of each open container.
+	var levels [][][]int
+	step := func() {
+		parent := levels[len(levels)-1]
+		tok := w.tokens[len(w.tokens)-1]
+		cur = make([][]int, len(pats))
+		for i, p := range pats {
+			cur[i] = p.step(parent[i], tok)
+		}
+	}
+	for !w.done() {
+		newOp, err := w.step()
+		if err != nil {
+			return nil, err
+		}
+
+		switch newOp {
+		case json.ScanBeginArray:
+			levels = append(levels, cur)
+			step()
+		case json.ScanObjectKey, json.ScanArrayValue:
+			step()
+		case json.ScanEndArray, json.ScanEndObject:
+			levels = levels[:len(levels)-1]
+			cur = nil
+		case json.ScanBeginObject:
+			levels = append(levels, cur)
+			cur = nil
+		}
+
+		if (newOp == json.ScanBeginArray || newOp == json.ScanArrayValue ||
+			newOp == json.ScanObjectKey) && accepts() {
+			offset := w.offset
+			if emptyArray(data[offset:]) {
+				// an empty array has no first element
+				continue
+			}
+			val, _, err := json.NextValue(data[offset:], &json.Scanner{})
+			if err != nil {
+				return nil, syntaxError(data)
+			}
+			rv = append(rv, span{w.pointer(), offset, offset + len(val)})
+		}
+	}
+
+	if err := w.finish(); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+// ValueMatch is a value found by GetAll or ReflectAll.
+type ValueMatch struct {
+	Pointer string
+	Value   interface{}
+}
+
+// GetAll gets every value matching a pointer pattern (as described
+// for FindAll) from decoded JSON.  Object keys are visited in sorted
+// order.
+func GetAll(m interface{}, pat string) []ValueMatch {
+	p := parsePattern(pat)
+	return walkAll(reflect.ValueOf(m), p, p.start(), nil, false, nil)
+}
+
+// ReflectAll gets every value matching a pointer pattern (as described
+// for FindAll) from a struct.  Map keys are visited in sorted order.
+func ReflectAll(o interface{}, pat string) []ValueMatch {
+	p := parsePattern(pat)
+	return walkAll(reflect.ValueOf(o), p, p.start(), nil, true, nil)
+}
+
+func walkAll(val reflect.Value, p pattern, states []int, tokens []string,
+	structs bool, rv []ValueMatch) []ValueMatch {
+
+	if p.accepts(states) {
+		var v interface{}
+		if val.IsValid() && val.CanInterface() {
+			v = val.Interface()
+		}
+		rv = append(rv, ValueMatch{Join(tokens...), v})
+	}
+
+	visit := func(tok string, child reflect.Value) {
+		if next := p.step(states, tok); len(next) > 0 {
+			rv = walkAll(child, p, next, append(tokens, tok), structs, rv)
+		}
+	}
+
+	val = reflectIndirect(val)
+	switch val.Kind() {
+	case reflect.Struct:
+		if structs {
+			fields := cachedTypeFields(val.Type())
+			for i, name := range fields.names {
+				visit(name, val.Field(i))
+			}
+		}
+	case reflect.Map:
+		if !structs && val.Type().Key().Kind() != reflect.String {
+			break
+		}
+		keys := val.MapKeys()
+		names := make([]string, len(keys))
+		byName := make(map[string]reflect.Value, len(keys))
+		for i, k := range keys {
+			names[i] = makeMapKeyName(k)
+			byName[names[i]] = k
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			visit(name, val.MapIndex(byName[name]))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			visit(strconv.Itoa(i), val.Index(i))
+		}
+	}
+	return rv
+}
@@ -0,0 +1,134 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dustin/gojson"
+)
+
+var patternSrc = `{
+  "users": [
+    {"name": "dustin", "id": 1, "tags": {"id": "x"}},
+    {"name": "marty", "id": 2},
+    {"id": 3}
+  ],
+  "*": {"name": "star"},
+  "id": 0
+}`
+
+func TestPatternStates(t *testing.T) {
+	p := parsePattern("/a/**/b/**")
+	if got := p.start(); !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("Expected start [0], got %v", got)
+	}
+	st := p.step(p.start(), "a")
+	if !reflect.DeepEqual(st, []int{1, 2}) {
+		t.Errorf("Expected [1 2] after a, got %v", st)
+	}
+	st = p.step(st, "b")
+	if !reflect.DeepEqual(st, []int{1, 2, 3, 4}) || !p.accepts(st) {
+		t.Errorf("Expected accepting [1 2 3 4] after b, got %v", st)
+	}
+	if st = p.step(p.start(), "x"); len(st) != 0 {
+		t.Errorf("Expected no states after x, got %v", st)
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	tests := []struct {
+		pattern string
+		exp     []string
+	}{
+		{"/users/*/name", []string{"/users/0/name", "/users/1/name"}},
+		{"/users/*/id", []string{"/users/0/id", "/users/1/id", "/users/2/id"}},
+		{"/**/id", []string{"/users/0/id", "/users/0/tags/id",
+			"/users/1/id", "/users/2/id", "/id"}},
+		{"/*/name", []string{"/*/name"}},
+		{"/users/1", []string{"/users/1"}},
+		{"/users/9/*", nil},
+		{"/nope/**", nil},
+		{"", []string{""}},
+	}
+
+	for _, test := range tests {
+		matches, err := FindAll([]byte(patternSrc), test.pattern)
+		if err != nil {
+			t.Errorf("Error finding %v: %v", test.pattern, err)
+		}
+		var got []string
+		for _, m := range matches {
+			got = append(got, m.Pointer)
+			exp, _ := Find([]byte(patternSrc), m.Pointer)
+			if !reflect.DeepEqual(exp, m.Value) {
+				t.Errorf("On %v at %v, expected %s, got %s",
+					test.pattern, m.Pointer, exp, m.Value)
+			}
+		}
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("On %v, expected %#v, got %#v", test.pattern, test.exp, got)
+		}
+	}
+}
+
+func TestFindAllEverything(t *testing.T) {
+	matches, err := FindAll([]byte(objSrc), "/**")
+	if err != nil {
+		t.Fatalf("Error finding everything: %v", err)
+	}
+	ptrs, err := ListPointers([]byte(objSrc))
+	if err != nil {
+		t.Fatalf("Error listing pointers: %v", err)
+	}
+	if len(matches) != len(ptrs) {
+		t.Fatalf("Expected %v matches, got %v", len(ptrs), len(matches))
+	}
+	for i, m := range matches {
+		if m.Pointer != ptrs[i] {
+			t.Errorf("Expected %v at %v, got %v", ptrs[i], i, m.Pointer)
+		}
+	}
+}
+
+func TestFindAllBroken(t *testing.T) {
+	got, err := FindAll([]byte(`{"a": [}`), "/**")
+	if err == nil {
+		t.Errorf("Expected error on broken input, got %v", got)
+	}
+}
+
+func TestGetAll(t *testing.T) {
+	m := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(patternSrc), &m); err != nil {
+		t.Fatalf("Error parsing: %v", err)
+	}
+
+	got := GetAll(m, "/**/id")
+	exp := []ValueMatch{
+		{"/id", 0.0},
+		{"/users/0/id", 1.0},
+		{"/users/0/tags/id", "x"},
+		{"/users/1/id", 2.0},
+		{"/users/2/id", 3.0},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %#v, got %#v", exp, got)
+	}
+}
+
+func TestReflectAll(t *testing.T) {
+	got := ReflectAll(input, "/addresses/*/*")
+	exp := []ValueMatch{
+		{"/addresses/0/street", "123 Sesame St."},
+		{"/addresses/0/Zip", "99099"},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %#v, got %#v", exp, got)
+	}
+
+	got = ReflectAll(input, "/MapIntKey/*")
+	exp = []ValueMatch{{"/MapIntKey/1", "one"}, {"/MapIntKey/2", "two"}}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %#v, got %#v", exp, got)
+	}
+}
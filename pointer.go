@@ -0,0 +1,253 @@
+package jsonpointer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EscapeToken escapes a key or index for use as a token of a pointer,
+// replacing "~" with "~0" and "/" with "~1".
+func EscapeToken(s string) string {
+	if strings.IndexAny(s, "~/") < 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '~':
+			b.WriteString("~0")
+		case '/':
+			b.WriteString("~1")
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// UnescapeToken reverses EscapeToken, reporting an error for a "~"
+// not followed by "0" or "1".
+func UnescapeToken(s string) (string, error) {
+	for i := strings.IndexByte(s, '~'); i >= 0; {
+		if i+1 == len(s) || (s[i+1] != '0' && s[i+1] != '1') {
+			return "", fmt.Errorf("invalid escape in JSON pointer token %q", s)
+		}
+		j := strings.IndexByte(s[i+2:], '~')
+		if j < 0 {
+			break
+		}
+		i += j + 2
+	}
+	return unescape(s), nil
+}
+
+// Join builds a pointer from unescaped tokens.
+func Join(tokens ...string) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteByte('/')
+		b.WriteString(EscapeToken(t))
+	}
+	return b.String()
+}
+
+// Split breaks a pointer into unescaped tokens, reporting an error if
+// it isn't empty and doesn't begin with "/", or has an invalid escape.
+// The empty pointer, which refers to the whole document, has no
+// tokens.
+func Split(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must be empty or begin with '/'", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		var err error
+		if tokens[i], err = UnescapeToken(t); err != nil {
+			return nil, fmt.Errorf("invalid JSON pointer %q: %w", pointer, err)
+		}
+	}
+	return tokens, nil
+}
+
+// SplitLenient is Split accepting any pointer, as the lookup functions
+// do: the first character is dropped whatever it is, and a "~" not
+// followed by "0" or "1" is kept as it is.
+func SplitLenient(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	if !strings.Contains(pointer, "~") {
+		return tokens
+	}
+	for i := range tokens {
+		tokens[i] = unescape(tokens[i])
+	}
+	return tokens
+}
+
+// unescape is UnescapeToken keeping invalid escapes as they are.
+func unescape(s string) string {
+	n := strings.Count(s, "~")
+	if n == 0 {
+		return s
+	}
+
+	t := make([]byte, len(s)-n+1) // remove one char per ~
+	w := 0
+	start := 0
+	for i := 0; i < n; i++ {
+		j := start + strings.Index(s[start:], "~")
+		w += copy(t[w:], s[start:j])
+		if len(s) < j+2 {
+			t[w] = '~'
+			w++
+			break
+		}
+		c := s[j+1]
+		switch c {
+		case '0':
+			t[w] = '~'
+			w++
+		case '1':
+			t[w] = '/'
+			w++
+		default:
+			t[w] = '~'
+			w++
+			t[w] = c
+			w++
+		}
+		start = j + 2
+	}
+	w += copy(t[w:], s[start:])
+	return string(t[0:w])
+}
+
+// Compare orders pointers by their tokens, returning -1, 0 or +1.  A
+// pointer sorts before its descendants, array indices sort
+// numerically and before any other token, and other tokens sort as
+// strings.  So "/a/2" < "/a/10" < "/a/b" < "/a/b/c" < "/a~1".
+func Compare(a, b string) int {
+	return compareTokens(SplitLenient(a), SplitLenient(b))
+}
+
+func compareTokens(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareToken(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+func compareToken(a, b string) int {
+	i, j := arrayIndex(a), arrayIndex(b)
+	switch {
+	case i >= 0 && j >= 0:
+		if i != j {
+			if i < j {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	case i >= 0:
+		return -1
+	case j >= 0:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+type pointerSorter struct {
+	pointers []string
+	tokens   [][]string
+}
+
+func (s pointerSorter) Len() int {
+	return len(s.pointers)
+}
+
+func (s pointerSorter) Less(i, j int) bool {
+	return compareTokens(s.tokens[i], s.tokens[j]) < 0
+}
+
+func (s pointerSorter) Swap(i, j int) {
+	s.pointers[i], s.pointers[j] = s.pointers[j], s.pointers[i]
+	s.tokens[i], s.tokens[j] = s.tokens[j], s.tokens[i]
+}
+
+// Sort sorts pointers in the order defined by Compare.
+func Sort(pointers []string) {
+	s := pointerSorter{pointers, make([][]string, len(pointers))}
+	for i, p := range pointers {
+		s.tokens[i] = SplitLenient(p)
+	}
+	sort.Stable(s)
+}
+
+// Depth returns the number of tokens in a pointer; the root pointer
+// has depth 0.
+func Depth(pointer string) int {
+	return len(SplitLenient(pointer))
+}
+
+// IsAncestor reports whether target is within the value at ancestor,
+// not counting ancestor itself.  Tokens are compared whole, so "/a"
+// is an ancestor of "/a/b" but not of "/ab".
+func IsAncestor(ancestor, target string) bool {
+	a, t := SplitLenient(ancestor), SplitLenient(target)
+	return len(a) < len(t) && commonPrefix(a, t) == len(a)
+}
+
+// CommonAncestor returns the longest pointer that is, or is an
+// ancestor of, every given pointer.  It returns "" (the root) when
+// there are no pointers.
+func CommonAncestor(pointers ...string) string {
+	if len(pointers) == 0 {
+		return ""
+	}
+	common := SplitLenient(pointers[0])
+	for _, p := range pointers[1:] {
+		common = common[:commonPrefix(common, SplitLenient(p))]
+	}
+	return Join(common...)
+}
+
+func commonPrefix(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// Rel returns the Relative JSON Pointer that refers to target when
+// evaluated from base: the number of levels to go up from base to
+// their common ancestor, followed by the pointer from there down to
+// target.  For example, Rel("/a/b/c", "/a/d") is "2/d".
+func Rel(base, target string) (string, error) {
+	b, err := Split(base)
+	if err != nil {
+		return "", err
+	}
+	t, err := Split(target)
+	if err != nil {
+		return "", err
+	}
+	n := commonPrefix(b, t)
+	return strconv.Itoa(len(b)-n) + Join(t[n:]...), nil
+}
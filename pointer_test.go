@@ -0,0 +1,213 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEscapeToken(t *testing.T) {
+	tests := map[string]string{
+		"":      "",
+		"a":     "a",
+		"a/b":   "a~1b",
+		"m~n":   "m~0n",
+		"~1":    "~01",
+		"/~/":   "~1~0~1",
+		"~~//~": "~0~0~1~1~0",
+	}
+	for in, exp := range tests {
+		if got := EscapeToken(in); got != exp {
+			t.Errorf("EscapeToken(%q) = %q, wanted %q", in, got, exp)
+		}
+		got, err := UnescapeToken(exp)
+		if err != nil || got != in {
+			t.Errorf("UnescapeToken(%q) = %q, %v, wanted %q", exp, got, err, in)
+		}
+	}
+}
+
+func TestUnescapeTokenInvalid(t *testing.T) {
+	for _, in := range []string{"~", "a~", "~2", "~0~", "~1~a"} {
+		got, err := UnescapeToken(in)
+		if err == nil {
+			t.Errorf("Expected error unescaping %q, got %q", in, got)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		tokens []string
+		exp    string
+	}{
+		{nil, ""},
+		{[]string{""}, "/"},
+		{[]string{"a", "b"}, "/a/b"},
+		{[]string{"a/b", "m~n", "0"}, "/a~1b/m~0n/0"},
+	}
+	for _, test := range tests {
+		if got := Join(test.tokens...); got != test.exp {
+			t.Errorf("Join(%q) = %q, wanted %q", test.tokens, got, test.exp)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := map[string][]string{
+		"":             nil,
+		"/":            {""},
+		"//":           {"", ""},
+		"/a/b":         {"a", "b"},
+		"/a~1b/m~0n/0": {"a/b", "m~n", "0"},
+		"/~01":         {"~1"},
+	}
+	for in, exp := range tests {
+		got, err := Split(in)
+		if err != nil || !reflect.DeepEqual(got, exp) {
+			t.Errorf("Split(%q) = %q, %v, wanted %q", in, got, err, exp)
+		}
+		if got := SplitLenient(in); !reflect.DeepEqual(got, exp) {
+			t.Errorf("SplitLenient(%q) = %q, wanted %q", in, got, exp)
+		}
+		if j := Join(exp...); j != in {
+			t.Errorf("Join(Split(%q)) = %q", in, j)
+		}
+	}
+}
+
+func TestSplitInvalid(t *testing.T) {
+	for _, in := range []string{"a", "a/b", "/~2", "/~", "/a/b~"} {
+		got, err := Split(in)
+		if err == nil {
+			t.Errorf("Expected error splitting %q, got %q", in, got)
+		}
+	}
+}
+
+func TestSplitLenient(t *testing.T) {
+	tests := map[string][]string{
+		"a/b":  {"", "b"},
+		"/~2":  {"~2"},
+		"/a/~": {"a", "~"},
+	}
+	for in, exp := range tests {
+		if got := SplitLenient(in); !reflect.DeepEqual(got, exp) {
+			t.Errorf("SplitLenient(%q) = %q, wanted %q", in, got, exp)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ordered := []string{
+		"", "/0", "/2", "/10", "/", "/01", "/a", "/a/0", "/a/b",
+		"/a/b/c", "/a~1", "/ab", "/b",
+	}
+	for i, a := range ordered {
+		for j, b := range ordered {
+			exp := 0
+			switch {
+			case i < j:
+				exp = -1
+			case i > j:
+				exp = 1
+			}
+			if got := Compare(a, b); got != exp {
+				t.Errorf("Compare(%q, %q) = %v, wanted %v", a, b, got, exp)
+			}
+		}
+	}
+
+	got := []string{"/b", "/a/10", "/a/b", "/a/2", "/a", "", "/a/b/c"}
+	Sort(got)
+	exp := []string{"", "/a", "/a/2", "/a/10", "/a/b", "/a/b/c", "/b"}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %q, got %q", exp, got)
+	}
+}
+
+func TestDepth(t *testing.T) {
+	tests := map[string]int{
+		"":       0,
+		"/":      1,
+		"/a":     1,
+		"/a~1b":  1,
+		"/a/0/b": 3,
+		"x":      1,
+		"x/y":    2,
+	}
+	for in, exp := range tests {
+		if got := Depth(in); got != exp {
+			t.Errorf("Depth(%q) = %v, wanted %v", in, got, exp)
+		}
+	}
+}
+
+func TestIsAncestor(t *testing.T) {
+	tests := []struct {
+		a, b string
+		exp  bool
+	}{
+		{"", "/a", true},
+		{"", "", false},
+		{"/a", "/a/b", true},
+		{"/a", "/a/b/c", true},
+		{"/a", "/a", false},
+		{"/a", "/ab", false},
+		{"/a/b", "/a", false},
+		{"/a~1b", "/a/b/c", false},
+	}
+	for _, test := range tests {
+		if got := IsAncestor(test.a, test.b); got != test.exp {
+			t.Errorf("IsAncestor(%q, %q) = %v, wanted %v",
+				test.a, test.b, got, test.exp)
+		}
+	}
+}
+
+func TestCommonAncestor(t *testing.T) {
+	tests := []struct {
+		in  []string
+		exp string
+	}{
+		{nil, ""},
+		{[]string{"/a/b"}, "/a/b"},
+		{[]string{"/a/b", "/a/c"}, "/a"},
+		{[]string{"/a/b/c", "/a/b", "/a/b/d"}, "/a/b"},
+		{[]string{"/ab", "/a"}, ""},
+		{[]string{"/a~1b/c", "/a~1b/d"}, "/a~1b"},
+	}
+	for _, test := range tests {
+		if got := CommonAncestor(test.in...); got != test.exp {
+			t.Errorf("CommonAncestor(%q) = %q, wanted %q", test.in, got, test.exp)
+		}
+	}
+}
+
+func TestRel(t *testing.T) {
+	tests := []struct {
+		base, target, exp string
+	}{
+		{"", "", "0"},
+		{"", "/a/b", "0/a/b"},
+		{"/a/b", "/a/b", "0"},
+		{"/a/b", "/a/b/c", "0/c"},
+		{"/a/b/c", "/a/d", "2/d"},
+		{"/a/b", "", "2"},
+		{"/a/0", "/a/1", "1/1"},
+		{"/x", "/m~0n", "1/m~0n"},
+	}
+	for _, test := range tests {
+		got, err := Rel(test.base, test.target)
+		if err != nil || got != test.exp {
+			t.Errorf("Rel(%q, %q) = %q, %v, wanted %q",
+				test.base, test.target, got, err, test.exp)
+		}
+	}
+
+	if got, err := Rel("a", "/b"); err == nil {
+		t.Errorf("Expected error for an invalid base, got %q", got)
+	}
+	if got, err := Rel("/a", "/~2"); err == nil {
+		t.Errorf("Expected error for an invalid target, got %q", got)
+	}
+}
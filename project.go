@@ -0,0 +1,89 @@
+package jsonpointer
+
+import (
+	"bytes"
+)
+
+// Project returns a document containing only the values at the given
+// pointers, at their original locations, in one pass through data.
+// The objects and arrays enclosing them are recreated with only the
+// members needed, in the order requested, and elements before one
+// requested from an array are null.  Pointers that aren't found, or
+// lie within another requested value, are ignored; if none are left,
+// the result is an empty container of the same kind as data.
+func Project(data []byte, paths []string) ([]byte, error) {
+	// Look up every requested pointer and its ancestors, whose
+	// kinds decide which containers to recreate.
+	tokens := make([][]string, len(paths))
+	index := map[string]int{}
+	var lookup []string
+	for i, p := range paths {
+		tokens[i] = SplitLenient(p)
+		for d := 0; d <= len(tokens[i]); d++ {
+			ptr := Join(tokens[i][:d]...)
+			if _, ok := index[ptr]; !ok {
+				index[ptr] = len(lookup)
+				lookup = append(lookup, ptr)
+			}
+		}
+	}
+	found, err := FindManyOrdered(data, lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := map[string]bool{}
+	for _, toks := range tokens {
+		if ptr := Join(toks...); found[index[ptr]] != nil {
+			requested[ptr] = true
+		}
+	}
+
+	if requested[""] {
+		return bytes.TrimSpace(data), nil
+	}
+
+	root := &docNode{}
+	root.isArray = firstByte(data) == '['
+	root.isObject = !root.isArray
+	placed := map[string]bool{}
+	for _, toks := range tokens {
+		ptr := Join(toks...)
+		if !requested[ptr] || placed[ptr] || withinRequested(toks, requested) {
+			continue
+		}
+		placed[ptr] = true
+		n, err := root.place(toks, func(i int) bool {
+			return firstByte(found[index[Join(toks[:i]...)]]) == '['
+		})
+		if err != nil {
+			return nil, &PointerError{ptr, err}
+		}
+		n.raw = bytes.TrimSpace(found[index[ptr]])
+	}
+
+	var b bytes.Buffer
+	root.write(&b, false)
+	return b.Bytes(), nil
+}
+
+// withinRequested reports whether a proper ancestor of toks is
+// requested.
+func withinRequested(toks []string, requested map[string]bool) bool {
+	for d := 0; d < len(toks); d++ {
+		if requested[Join(toks[:d]...)] {
+			return true
+		}
+	}
+	return false
+}
+
+// firstByte returns the first byte of raw JSON that isn't whitespace.
+func firstByte(data []byte) byte {
+	for _, c := range data {
+		if !isSpace(rune(c)) {
+			return c
+		}
+	}
+	return 0
+}
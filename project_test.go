@@ -0,0 +1,53 @@
+package jsonpointer
+
+import (
+	"testing"
+)
+
+const projectSrc = `{"user": {"name": "ann", "email": "a@example.com",
+	"roles": ["admin", {"id": 2, "scope": "all"}], "0": "zero"},
+	"meta": {"n": 1}}`
+
+func TestProject(t *testing.T) {
+	tests := []struct {
+		paths []string
+		exp   string
+	}{
+		{[]string{"/user/email", "/user/name"},
+			`{"user":{"email":"a@example.com","name":"ann"}}`},
+		{[]string{"/user/roles/1/id", "/user/0", "/nope", "/meta"},
+			`{"user":{"roles":[null,{"id":2}],"0":"zero"},"meta":{"n": 1}}`},
+		{[]string{"/user/roles/1/scope", "/user/roles", "/user/roles"},
+			`{"user":{"roles":["admin", {"id": 2, "scope": "all"}]}}`},
+		{[]string{"/user/roles/01", "/user/roles/9"}, `{}`},
+		{nil, `{}`},
+		{[]string{"/meta", ""}, projectSrc},
+	}
+	for _, test := range tests {
+		got, err := Project([]byte(projectSrc), test.paths)
+		if err != nil {
+			t.Errorf("Error projecting %v: %v", test.paths, err)
+			continue
+		}
+		if string(got) != test.exp {
+			t.Errorf("Projecting %v, expected %s, got %s", test.paths, test.exp, got)
+		}
+	}
+}
+
+func TestProjectArrayRoot(t *testing.T) {
+	got, err := Project([]byte(` [{"a": 1, "b": 2}, 3] `), []string{"/0/b", "/2"})
+	if err != nil || string(got) != `[{"b":2}]` {
+		t.Errorf("Expected [{\"b\":2}], got %s/%v", got, err)
+	}
+	got, err = Project([]byte(`[1]`), []string{"/x"})
+	if err != nil || string(got) != `[]` {
+		t.Errorf("Expected [], got %s/%v", got, err)
+	}
+}
+
+func TestProjectBroken(t *testing.T) {
+	if got, err := Project([]byte(`{"a": [}`), []string{"/a/0"}); err == nil {
+		t.Errorf("Expected error projecting broken JSON, got %s", got)
+	}
+}
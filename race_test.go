@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package jsonpointer
+
+// raceEnabled is set when the race detector, which defeats pooling,
+// is on.
+const raceEnabled = true
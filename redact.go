@@ -0,0 +1,105 @@
+package jsonpointer
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dustin/gojson"
+)
+
+// RedactOptions controls how values are redacted.
+type RedactOptions struct {
+	// PreserveType replaces values whose type differs from the
+	// replacement's with a placeholder of their own type: strings
+	// with the replacement's text as a string, numbers with 0,
+	// booleans with false, objects with {} and arrays with [].
+	PreserveType bool
+}
+
+// Redact replaces the values at the given pointers or pointer
+// patterns (as described for FindAll) with the raw JSON replacement,
+// leaving every other byte of data as it was.  Where one redacted
+// value lies within another, the outer one is replaced.
+func Redact(data []byte, paths []string, replacement []byte) ([]byte, error) {
+	return RedactWith(data, paths, replacement, RedactOptions{})
+}
+
+// RedactWith is Redact with options.
+func RedactWith(data []byte, paths []string, replacement []byte, opts RedactOptions) ([]byte, error) {
+	if !validValue(replacement) {
+		return nil, fmt.Errorf("invalid JSON replacement %q", replacement)
+	}
+	replacement = bytes.TrimSpace(replacement)
+
+	pats := make([]pattern, len(paths))
+	for i, p := range paths {
+		pats[i] = parsePattern(p)
+	}
+	spans, err := findAll(data, pats)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	prev := 0
+	for _, s := range spans {
+		if s.start < prev {
+			// Within a value already redacted.
+			continue
+		}
+		val := bytes.TrimSpace(data[s.start:s.end])
+		if len(val) == 0 {
+			continue
+		}
+		start := s.start + bytes.Index(data[s.start:s.end], val)
+		b.Write(data[prev:start])
+		b.Write(redaction(val, replacement, opts))
+		prev = start + len(val)
+	}
+	b.Write(data[prev:])
+	return b.Bytes(), nil
+}
+
+// redaction returns what val is to be replaced with.
+func redaction(val, replacement []byte, opts RedactOptions) []byte {
+	if !opts.PreserveType || valueKind(val) == valueKind(replacement) {
+		return replacement
+	}
+	switch valueKind(val) {
+	case '"':
+		if valueKind(replacement) == '"' {
+			return replacement
+		}
+		quoted, _ := json.Marshal(string(replacement))
+		return quoted
+	case '0':
+		return []byte("0")
+	case 't':
+		return []byte("false")
+	case '{':
+		return []byte("{}")
+	case '[':
+		return []byte("[]")
+	}
+	return []byte("null")
+}
+
+// valueKind classifies a raw JSON value by its first byte, with all
+// numbers as '0' and both booleans as 't'.
+func valueKind(val []byte) byte {
+	switch c := val[0]; c {
+	case '"', '{', '[', 'n':
+		return c
+	case 't', 'f':
+		return 't'
+	}
+	return '0'
+}
+
+// validValue reports whether raw is a single JSON value, possibly
+// surrounded by whitespace.
+func validValue(raw []byte) bool {
+	val, err := nextValue(raw, &json.Scanner{})
+	return err == nil && len(bytes.TrimSpace(raw[len(val):])) == 0 &&
+		len(bytes.TrimSpace(val)) > 0
+}
@@ -0,0 +1,78 @@
+package jsonpointer
+
+import (
+	"testing"
+)
+
+const redactSrc = `{
+  "user": {"name": "ann", "password": "hunter2", "pin": 1234},
+  "tokens": [ {"password": "x"}, {"password": {"old": "y"}} ],
+  "flag": true
+}`
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		paths []string
+		exp   string
+	}{
+		{[]string{"/user/password", "/tokens/**/password"}, `{
+  "user": {"name": "ann", "password": "***", "pin": 1234},
+  "tokens": [ {"password": "***"}, {"password": "***"} ],
+  "flag": true
+}`},
+		{[]string{"/**/password", "/tokens", "/nope"}, `{
+  "user": {"name": "ann", "password": "***", "pin": 1234},
+  "tokens": "***",
+  "flag": true
+}`},
+		{[]string{"/user/pin", "/flag", "/tokens/1/password/old"}, `{
+  "user": {"name": "ann", "password": "hunter2", "pin": "***"},
+  "tokens": [ {"password": "x"}, {"password": {"old": "***"}} ],
+  "flag": "***"
+}`},
+		{nil, redactSrc},
+		{[]string{""}, `"***"`},
+	}
+	for _, test := range tests {
+		got, err := Redact([]byte(redactSrc), test.paths, []byte(`"***"`))
+		if err != nil {
+			t.Errorf("Error redacting %v: %v", test.paths, err)
+			continue
+		}
+		if string(got) != test.exp {
+			t.Errorf("Redacting %v, expected\n%s\ngot\n%s", test.paths, test.exp, got)
+		}
+	}
+}
+
+func TestRedactPreserveType(t *testing.T) {
+	paths := []string{"/user/*", "/tokens/1/password", "/flag", "/tokens/0"}
+	exp := `{
+  "user": {"name": "***", "password": "***", "pin": 0},
+  "tokens": [ {}, {"password": {}} ],
+  "flag": false
+}`
+	opts := RedactOptions{PreserveType: true}
+	got, err := RedactWith([]byte(redactSrc), paths, []byte(` "***" `), opts)
+	if err != nil || string(got) != exp {
+		t.Errorf("Expected\n%s\ngot\n%s/%v", exp, got, err)
+	}
+
+	exp = `{"a": "null", "b": null, "c": 0}`
+	got, err = RedactWith([]byte(`{"a": "s", "b": null, "c": 1.5}`),
+		[]string{"/*"}, []byte("null"), opts)
+	if err != nil || string(got) != exp {
+		t.Errorf("Expected %s, got %s/%v", exp, got, err)
+	}
+}
+
+func TestRedactErrors(t *testing.T) {
+	for _, r := range []string{"", "***", `"a" "b"`} {
+		if got, err := Redact([]byte(redactSrc), []string{"/flag"}, []byte(r)); err == nil {
+			t.Errorf("Expected error redacting with %q, got %s", r, got)
+		}
+	}
+	if got, err := Redact([]byte(`{"a": [}`), []string{"/a"}, []byte("0")); err == nil {
+		t.Errorf("Expected error redacting broken JSON, got %s", got)
+	}
+}
@@ -0,0 +1,274 @@
+package jsonpointer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dustin/gojson"
+)
+
+// ErrCircularRef is reported when JSON References refer back to
+// themselves.
+var ErrCircularRef = errors.New("circular reference")
+
+// A Loader fetches the raw JSON of the document at a URI.
+type Loader interface {
+	Load(uri string) ([]byte, error)
+}
+
+// LoaderFunc adapts a function to a Loader.
+type LoaderFunc func(uri string) ([]byte, error)
+
+// Load calls f(uri).
+func (f LoaderFunc) Load(uri string) ([]byte, error) {
+	return f(uri)
+}
+
+// FSLoader loads documents from fsys, treating URIs as slash
+// separated paths within it.
+func FSLoader(fsys fs.FS) Loader {
+	return LoaderFunc(func(uri string) ([]byte, error) {
+		return fs.ReadFile(fsys, strings.TrimPrefix(uri, "/"))
+	})
+}
+
+// DirLoader loads documents from the operating system's file system,
+// treating URIs as slash separated paths relative to dir.  It is
+// FSLoader(os.DirFS(dir)), so paths with ".." elements, which could
+// lead out of dir, aren't loaded.
+func DirLoader(dir string) Loader {
+	return FSLoader(os.DirFS(dir))
+}
+
+// Ref is a JSON Reference found in a document.
+type Ref struct {
+	// Pointer is the location of the {"$ref": ...} object.
+	Pointer string
+	// URI is the value of its $ref member.
+	URI string
+}
+
+// FindRefs lists the JSON References in a document in document order.
+func FindRefs(data []byte) ([]Ref, error) {
+	matches, err := FindAll(data, "/**/$ref")
+	if err != nil {
+		return nil, err
+	}
+	var rv []Ref
+	for _, m := range matches {
+		var uri string
+		if json.Unmarshal(m.Value, &uri) != nil {
+			// Not a reference, just a member named $ref.
+			continue
+		}
+		rv = append(rv, Ref{strings.TrimSuffix(m.Pointer, "/$ref"), uri})
+	}
+	return rv, nil
+}
+
+// A Resolver resolves JSON References of the form
+// {"$ref": "other.json#/a/b"} within and across documents, loading
+// each document at most once.  Relative references are resolved
+// against the URI of the document containing them, and fragments are
+// evaluated as JSON Pointers.
+//
+// A Resolver is safe for concurrent use.
+type Resolver struct {
+	loader Loader
+
+	mu      sync.Mutex
+	raw     map[string][]byte
+	decoded map[string]interface{}
+}
+
+// NewResolver creates a Resolver loading documents with l, which may
+// be nil if every document is added with Add.
+func NewResolver(l Loader) *Resolver {
+	return &Resolver{
+		loader:  l,
+		raw:     map[string][]byte{},
+		decoded: map[string]interface{}{},
+	}
+}
+
+// Add makes a document available at the given URI without loading it.
+func (r *Resolver) Add(uri string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.raw[uri] = data
+	delete(r.decoded, uri)
+}
+
+func (r *Resolver) load(uri string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d, ok := r.raw[uri]; ok {
+		return d, nil
+	}
+	if r.loader == nil {
+		return nil, fmt.Errorf("no loader for %q", uri)
+	}
+	d, err := r.loader.Load(uri)
+	if err != nil {
+		return nil, fmt.Errorf("loading %q: %w", uri, err)
+	}
+	r.raw[uri] = d
+	return d, nil
+}
+
+func (r *Resolver) loadDecoded(uri string) (interface{}, error) {
+	d, err := r.load(uri)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if v, ok := r.decoded[uri]; ok {
+		return v, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(d, &v); err != nil {
+		return nil, fmt.Errorf("decoding %q: %w", uri, err)
+	}
+	r.decoded[uri] = v
+	return v, nil
+}
+
+// splitRef resolves ref against base and splits it into a document
+// URI and a JSON Pointer.
+func splitRef(base, ref string) (string, string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", "", err
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", "", err
+	}
+	relative := !b.IsAbs() && !u.IsAbs() &&
+		!strings.HasPrefix(base, "/") && !strings.HasPrefix(ref, "/")
+	u = b.ResolveReference(u)
+	if relative {
+		// Keep relative file names relative.
+		u.Path = strings.TrimPrefix(u.Path, "/")
+	}
+	ptr := u.Fragment
+	if ptr != "" && ptr[0] != '/' {
+		return "", "", fmt.Errorf("unsupported fragment in %q: not a JSON pointer", ref)
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String(), ptr, nil
+}
+
+// Resolve returns the raw JSON a URI such as "doc.json#/a/b" refers
+// to.  If that is itself a reference, it is followed in turn; any
+// references nested deeper within it are left alone.
+func (r *Resolver) Resolve(uri string) ([]byte, error) {
+	doc, ptr, err := splitRef("", uri)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for {
+		key := doc + "#" + ptr
+		if seen[key] {
+			return nil, fmt.Errorf("%w through %q", ErrCircularRef, key)
+		}
+		seen[key] = true
+
+		data, err := r.load(doc)
+		if err != nil {
+			return nil, err
+		}
+		val, err := Find(data, ptr)
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			return nil, &PointerError{key, ErrNotFound}
+		}
+
+		var next string
+		if FindDecode(val, "/$ref", &next) != nil {
+			return val, nil
+		}
+		if doc, ptr, err = splitRef(doc, next); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Inline returns the decoded value a URI refers to with every
+// reference within it replaced, recursively, by the value it refers
+// to.  References that lead back to themselves cannot be inlined and
+// produce an error wrapping ErrCircularRef.
+func (r *Resolver) Inline(uri string) (interface{}, error) {
+	return r.inlineRef("", uri, nil)
+}
+
+// InlineJSON is Inline producing JSON.
+func (r *Resolver) InlineJSON(uri string) ([]byte, error) {
+	v, err := r.Inline(uri)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func (r *Resolver) inlineRef(base, ref string, stack []string) (interface{}, error) {
+	doc, ptr, err := splitRef(base, ref)
+	if err != nil {
+		return nil, err
+	}
+	key := doc + "#" + ptr
+	for _, s := range stack {
+		if s == key {
+			return nil, fmt.Errorf("%w through %q", ErrCircularRef, key)
+		}
+	}
+
+	root, err := r.loadDecoded(doc)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := GetOK(root, ptr)
+	if !ok {
+		return nil, &PointerError{key, ErrNotFound}
+	}
+	return r.inline(v, doc, append(stack, key))
+}
+
+func (r *Resolver) inline(v interface{}, base string, stack []string) (interface{}, error) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := x["$ref"].(string); ok {
+			return r.inlineRef(base, ref, stack)
+		}
+		rv := make(map[string]interface{}, len(x))
+		for k, c := range x {
+			ic, err := r.inline(c, base, stack)
+			if err != nil {
+				return nil, err
+			}
+			rv[k] = ic
+		}
+		return rv, nil
+	case []interface{}:
+		rv := make([]interface{}, len(x))
+		for i, c := range x {
+			ic, err := r.inline(c, base, stack)
+			if err != nil {
+				return nil, err
+			}
+			rv[i] = ic
+		}
+		return rv, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,193 @@
+package jsonpointer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dustin/gojson"
+)
+
+var refFS = fstest.MapFS{
+	"main.json": {Data: []byte(`{
+  "definitions": {
+    "name": {"type": "string"},
+    "alias": {"$ref": "#/definitions/name"},
+    "person": {"properties": {
+      "name": {"$ref": "#/definitions/name"},
+      "address": {"$ref": "common/address.json#/definitions/address"}
+    }}
+  },
+  "properties": {"$ref": {"type": "boolean"}}
+}`)},
+	"common/address.json": {Data: []byte(`{
+  "definitions": {
+    "address": {"properties": {"zip": {"$ref": "#/definitions/zip"}}},
+    "zip": {"type": "string", "pattern": "^[0-9]{5}$"}
+  }
+}`)},
+	"cycle.json": {Data: []byte(`{
+  "a": {"$ref": "#/b"},
+  "b": {"$ref": "#/a"},
+  "tree": {"children": {"items": {"$ref": "#/tree"}}}
+}`)},
+}
+
+func TestFindRefs(t *testing.T) {
+	refs, err := FindRefs(refFS["main.json"].Data)
+	if err != nil {
+		t.Fatalf("Error finding refs: %v", err)
+	}
+	exp := []Ref{
+		{"/definitions/alias", "#/definitions/name"},
+		{"/definitions/person/properties/name", "#/definitions/name"},
+		{"/definitions/person/properties/address", "common/address.json#/definitions/address"},
+	}
+	if !reflect.DeepEqual(refs, exp) {
+		t.Errorf("Expected %#v, got %#v", exp, refs)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	r := NewResolver(FSLoader(refFS))
+
+	got, err := r.Resolve("main.json#/definitions/alias")
+	if err != nil {
+		t.Fatalf("Error resolving alias: %v", err)
+	}
+	if string(got) != ` {"type": "string"}` {
+		t.Errorf("Expected the name definition, got %q", got)
+	}
+
+	got, err = r.Resolve("main.json#/definitions/person/properties/address")
+	if err != nil {
+		t.Fatalf("Error resolving address: %v", err)
+	}
+	exp := ` {"properties": {"zip": {"$ref": "#/definitions/zip"}}}`
+	if string(got) != exp {
+		t.Errorf("Expected %q, got %q", exp, got)
+	}
+
+	_, err = r.Resolve("main.json#/definitions/missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+
+	_, err = r.Resolve("cycle.json#/a")
+	if !errors.Is(err, ErrCircularRef) {
+		t.Errorf("Expected circular reference, got %v", err)
+	}
+
+	_, err = r.Resolve("missing.json")
+	if err == nil {
+		t.Errorf("Expected error loading a missing document")
+	}
+
+	_, err = r.Resolve("main.json#anchor")
+	if err == nil {
+		t.Errorf("Expected error on a non-pointer fragment")
+	}
+}
+
+func TestInline(t *testing.T) {
+	r := NewResolver(FSLoader(refFS))
+
+	got, err := r.InlineJSON("main.json#/definitions/person")
+	if err != nil {
+		t.Fatalf("Error inlining: %v", err)
+	}
+	var gotv, expv interface{}
+	if err := json.Unmarshal(got, &gotv); err != nil {
+		t.Fatalf("Error parsing inlined result: %v", err)
+	}
+	json.Unmarshal([]byte(`{"properties": {
+      "name": {"type": "string"},
+      "address": {"properties": {"zip": {"type": "string", "pattern": "^[0-9]{5}$"}}}
+    }}`), &expv)
+	if !reflect.DeepEqual(gotv, expv) {
+		t.Errorf("Expected %v, got %s", expv, got)
+	}
+
+	v, err := r.Inline("main.json")
+	if err != nil {
+		t.Fatalf("Error inlining the whole document: %v", err)
+	}
+	if Get(v, "/properties/$ref/type") != "boolean" {
+		t.Errorf("Expected a non-string $ref member to be kept, got %v", v)
+	}
+
+	_, err = r.Inline("cycle.json#/tree")
+	if !errors.Is(err, ErrCircularRef) {
+		t.Errorf("Expected circular reference, got %v", err)
+	}
+}
+
+func TestResolverAdd(t *testing.T) {
+	r := NewResolver(nil)
+	r.Add("a.json", []byte(`{"x": {"$ref": "b.json#/y"}}`))
+	r.Add("b.json", []byte(`{"y": 42}`))
+
+	v, err := r.Inline("a.json")
+	if err != nil {
+		t.Fatalf("Error inlining: %v", err)
+	}
+	if !reflect.DeepEqual(v, map[string]interface{}{"x": 42.0}) {
+		t.Errorf("Expected x: 42, got %v", v)
+	}
+
+	if _, err = r.Resolve("c.json"); err == nil {
+		t.Errorf("Expected error without a loader")
+	}
+}
+
+func TestDirLoader(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"a.json":     `{"b": {"$ref": "sub/b.json#/c"}}`,
+		"sub/b.json": `{"c": {"$ref": "../a.json#/d"}}`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewResolver(DirLoader(dir))
+	_, err := r.Resolve("a.json#/b")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected to follow to a missing /d, got %v", err)
+	}
+}
+
+func TestDirLoaderEscape(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "docs")
+	if err := os.Mkdir(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(parent, "secret.json")
+	if err := os.WriteFile(secret, []byte(`{"password": "x"}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	err := os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"$ref": "../secret.json"}`), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := DirLoader(dir)
+	for _, uri := range []string{"../secret.json", "a/../../secret.json",
+		filepath.ToSlash(secret)} {
+		if got, err := l.Load(uri); err == nil {
+			t.Errorf("Expected error loading %q from %v, got %s", uri, dir, got)
+		}
+	}
+	if got, err := NewResolver(l).Resolve("a.json"); err == nil {
+		t.Errorf("Expected error resolving outside %v, got %s", dir, got)
+	}
+}
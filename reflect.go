@@ -4,110 +4,230 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+// structFields describes how pointer tokens address the fields of a
+// struct type.
+type structFields struct {
+	// byToken maps a token to the index of the first field whose
+	// json tag name or Go name matches it.
+	byToken map[string]int
+	// names holds the token used to list each field, by index.
+	names []string
+}
+
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+// cachedTypeFields is like typeFields but uses a cache to avoid
+// repeated work.
+func cachedTypeFields(t reflect.Type) *structFields {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(*structFields)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.(*structFields)
+}
+
+// typeFields builds the token table for the given struct type.
+func typeFields(t reflect.Type) *structFields {
+	rv := &structFields{
+		byToken: make(map[string]int, t.NumField()*2),
+		names:   make([]string, t.NumField()),
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := parseJSONTagName(sf.Tag.Get("json"))
+		if name != "" {
+			if _, exists := rv.byToken[name]; !exists {
+				rv.byToken[name] = i
+			}
+			rv.names[i] = name
+		} else {
+			rv.names[i] = sf.Name
+		}
+		if _, exists := rv.byToken[sf.Name]; !exists {
+			rv.byToken[sf.Name] = i
+		}
+	}
+	return rv
+}
+
 // Reflect gets the value at the specified path from a struct.
 func Reflect(o interface{}, path string) interface{} {
 	if path == "" {
 		return o
 	}
 
-	parts := parsePointer(path)
-	var rv interface{} = o
+	return reflectPath(o, SplitLenient(path), nil)
+}
 
-OUTER:
-	for _, p := range parts {
-		val := reflect.ValueOf(rv)
-		if val.Kind() == reflect.Ptr {
-			val = val.Elem()
-		}
+// ReflectOK is like Reflect, but also reports whether a value exists
+// at the path, distinguishing a nil value from a missing one.
+func ReflectOK(o interface{}, path string) (interface{}, bool) {
+	val, err := ReflectValue(o, path)
+	if err != nil || !val.IsValid() {
+		return nil, err == nil
+	}
+	return val.Interface(), true
+}
+
+// ReflectValue gets the value at the specified path from a struct as
+// a reflect.Value.
+//
+// When o is a pointer, values reached through struct fields, arrays
+// and slices remain addressable, so they may be set in place.  Map
+// elements are never addressable.
+func ReflectValue(o interface{}, path string) (reflect.Value, error) {
+	val := reflect.ValueOf(o)
+	if path == "" {
+		return val, nil
+	}
+
+	val, ok := reflectValuePath(val, SplitLenient(path), nil)
+	if !ok {
+		return reflect.Value{}, &PointerError{path, ErrNotFound}
+	}
+	return val, nil
+}
+
+// reflectPath walks the given tokens from o.  If indices is non-nil,
+// it holds each token pre-converted to an array index (or -1).
+func reflectPath(o interface{}, parts []string, indices []int) interface{} {
+	val, ok := reflectValuePath(reflect.ValueOf(o), parts, indices)
+	if !ok || !val.IsValid() {
+		return nil
+	}
+	return val.Interface()
+}
+
+// reflectValuePath walks the given tokens from val without copying
+// anything out along the way.
+func reflectValuePath(val reflect.Value, parts []string, indices []int) (reflect.Value, bool) {
+	for n, p := range parts {
+		val = reflectIndirect(val)
 
 		if val.Kind() == reflect.Struct {
-			typ := val.Type()
-			for i := 0; i < typ.NumField(); i++ {
-				sf := typ.Field(i)
-				tag := sf.Tag.Get("json")
-				name := parseJSONTagName(tag)
-				if (name != "" && name == p) || sf.Name == p {
-					rv = val.Field(i).Interface()
-					continue OUTER
-				}
+			i, ok := cachedTypeFields(val.Type()).byToken[p]
+			if !ok {
+				// Found no matching field.
+				return reflect.Value{}, false
 			}
-			// Found no matching field.
-			return nil
+			val = val.Field(i)
 		} else if val.Kind() == reflect.Map {
 			// our pointer always gives us a string key
 			// here we try to convert it into the correct type
 			mapKey, canConvert := makeMapKeyFromString(val.Type().Key(), p)
-			if canConvert {
-				field := val.MapIndex(mapKey)
-				if field.IsValid() {
-					rv = field.Interface()
-				} else {
-					return nil
-				}
-			} else {
-				return nil
+			if !canConvert {
+				return reflect.Value{}, false
+			}
+			val = val.MapIndex(mapKey)
+			if !val.IsValid() {
+				return reflect.Value{}, false
 			}
 		} else if val.Kind() == reflect.Slice || val.Kind() == reflect.Array {
-			i, err := strconv.Atoi(p)
-			if err == nil && i < val.Len() {
-				rv = val.Index(i).Interface()
-			} else {
-				return nil
+			i := tokenIndex(parts, indices, n)
+			if i < 0 || i >= val.Len() {
+				return reflect.Value{}, false
 			}
+			val = val.Index(i)
 		} else {
-			return nil
+			return reflect.Value{}, false
 		}
 	}
 
-	return rv
+	return val, true
+}
+
+// reflectIndirect follows pointers and interfaces down to the value
+// they hold.
+func reflectIndirect(val reflect.Value) reflect.Value {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		val = val.Elem()
+	}
+	return val
+}
+
+// PointerValue is a pointer and the value it refers to.
+type PointerValue struct {
+	Pointer string
+	Value   reflect.Value
 }
 
 // ReflectListPointers lists all possible pointers from the given struct.
 func ReflectListPointers(o interface{}) ([]string, error) {
-	return reflectListPointersRecursive(o, ""), nil
+	return ReflectListPointersWith(o, ListOptions{})
 }
 
-func reflectListPointersRecursive(o interface{}, prefix string) []string {
-	rv := []string{prefix + ""}
+// ReflectListPointersWith lists the pointers from the given struct
+// selected by the options.
+func ReflectListPointersWith(o interface{}, opts ListOptions) ([]string, error) {
+	vals, err := ReflectListValuesWith(o, opts)
+	if err != nil {
+		return nil, err
+	}
+	rv := make([]string, len(vals))
+	for i, v := range vals {
+		rv[i] = v.Pointer
+	}
+	return rv, nil
+}
 
+// ReflectListValues lists all possible pointers from the given struct
+// along with the value of each, in the same order as
+// ReflectListPointers.  Values are addressable as they would be from
+// ReflectValue.
+func ReflectListValues(o interface{}) ([]PointerValue, error) {
+	return ReflectListValuesWith(o, ListOptions{})
+}
+
+// ReflectListValuesWith is ReflectListValues restricted by the
+// options.
+func ReflectListValuesWith(o interface{}, opts ListOptions) ([]PointerValue, error) {
 	val := reflect.ValueOf(o)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
+	depth := 0
+	if opts.Prefix != "" {
+		var err error
+		val, err = ReflectValue(o, opts.Prefix)
+		if err != nil {
+			return nil, nil
+		}
+		depth = len(SplitLenient(opts.Prefix))
 	}
+	return reflectListRecursive(val, opts.Prefix, depth, &opts, nil), nil
+}
 
-	if val.Kind() == reflect.Struct {
+func reflectListRecursive(val reflect.Value, prefix string, depth int,
+	opts *ListOptions, rv []PointerValue) []PointerValue {
 
-		typ := val.Type()
-		for i := 0; i < typ.NumField(); i++ {
-			child := val.Field(i).Interface()
-			sf := typ.Field(i)
-			tag := sf.Tag.Get("json")
-			name := parseJSONTagName(tag)
-			if name != "" {
-				// use the tag name
-				childReults := reflectListPointersRecursive(child, prefix+encodePointer([]string{name}))
-				rv = append(rv, childReults...)
-			} else {
-				// use the original field name
-				childResults := reflectListPointersRecursive(child, prefix+encodePointer([]string{sf.Name}))
-				rv = append(rv, childResults...)
-			}
-		}
+	if opts.MaxDepth > 0 && depth > opts.MaxDepth {
+		return rv
+	}
+
+	ival := reflectIndirect(val)
+	kind := ival.Kind()
+	container := kind == reflect.Struct || kind == reflect.Map ||
+		kind == reflect.Slice || kind == reflect.Array
+	if opts.wants(depth, container) {
+		rv = append(rv, PointerValue{prefix, val})
+	}
 
+	val = ival
+	depth++
+	if val.Kind() == reflect.Struct {
+		fields := cachedTypeFields(val.Type())
+		for i, name := range fields.names {
+			// use the tag name, or the original field name
+			rv = reflectListRecursive(val.Field(i), prefix+"/"+EscapeToken(name), depth, opts, rv)
+		}
 	} else if val.Kind() == reflect.Map {
 		for _, k := range val.MapKeys() {
-			child := val.MapIndex(k).Interface()
 			mapKeyName := makeMapKeyName(k)
-			childReults := reflectListPointersRecursive(child, prefix+encodePointer([]string{mapKeyName}))
-			rv = append(rv, childReults...)
+			rv = reflectListRecursive(val.MapIndex(k), prefix+"/"+EscapeToken(mapKeyName), depth, opts, rv)
 		}
 	} else if val.Kind() == reflect.Slice || val.Kind() == reflect.Array {
 		for i := 0; i < val.Len(); i++ {
-			child := val.Index(i).Interface()
-			childResults := reflectListPointersRecursive(child, prefix+encodePointer([]string{strconv.Itoa(i)}))
-			rv = append(rv, childResults...)
+			rv = reflectListRecursive(val.Index(i), prefix+"/"+EscapeToken(strconv.Itoa(i)), depth, opts, rv)
 		}
 	}
 	return rv
@@ -261,3 +261,213 @@ func TestReflect(t *testing.T) {
 		}
 	}
 }
+
+// linearField is the field lookup Reflect used before field tables
+// were cached per type.
+func linearField(typ reflect.Type, p string) int {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		name := parseJSONTagName(sf.Tag.Get("json"))
+		if (name != "" && name == p) || sf.Name == p {
+			return i
+		}
+	}
+	return -1
+}
+
+type wide struct {
+	A, B, C, D, E, F, G, H int
+	I                      int `json:"i,omitempty"`
+	J                      int `json:"B"`
+	K                      int `json:"kay"`
+	L, M, N, O, P, Q, R    int
+	Last                   int `json:"last"`
+}
+
+func TestTypeFieldsMatchLinear(t *testing.T) {
+	for _, typ := range []reflect.Type{
+		reflect.TypeOf(person{}), reflect.TypeOf(address{}),
+		reflect.TypeOf(wide{}),
+	} {
+		fields := cachedTypeFields(typ)
+		tokens := []string{"", "missing", "i", "I", "B", "J", "kay", "K", "last", "Last"}
+		for i := 0; i < typ.NumField(); i++ {
+			tokens = append(tokens, typ.Field(i).Name, fields.names[i])
+		}
+		for _, tok := range tokens {
+			exp := linearField(typ, tok)
+			got, ok := fields.byToken[tok]
+			if !ok {
+				got = -1
+			}
+			if got != exp {
+				t.Errorf("On %v %q, expected field %v, got %v", typ, tok, exp, got)
+			}
+		}
+	}
+}
+
+func TestTypeFieldsCached(t *testing.T) {
+	typ := reflect.TypeOf(wide{})
+	if cachedTypeFields(typ) != cachedTypeFields(typ) {
+		t.Errorf("Expected the same field table for repeated lookups")
+	}
+}
+
+func BenchmarkReflectFieldLinear(b *testing.B) {
+	typ := reflect.TypeOf(wide{})
+	for i := 0; i < b.N; i++ {
+		if linearField(typ, "last") < 0 {
+			b.FailNow()
+		}
+	}
+}
+
+func BenchmarkReflectFieldCached(b *testing.B) {
+	typ := reflect.TypeOf(wide{})
+	for i := 0; i < b.N; i++ {
+		if _, ok := cachedTypeFields(typ).byToken["last"]; !ok {
+			b.FailNow()
+		}
+	}
+}
+
+func BenchmarkReflectWideLast(b *testing.B) {
+	w := &wide{Last: 1}
+	for i := 0; i < b.N; i++ {
+		if Reflect(w, "/last") == nil {
+			b.FailNow()
+		}
+	}
+}
+
+func TestReflectValue(t *testing.T) {
+	p := &person{
+		Name:      "marty",
+		Aliases:   []string{"jabroni"},
+		Addresses: []*address{{Street: "123 Sesame St."}},
+		NestedMap: map[string]float64{"pi": 3.14},
+	}
+
+	v, err := ReflectValue(p, "/name")
+	if err != nil {
+		t.Fatalf("Error reflecting /name: %v", err)
+	}
+	v.SetString("dustin")
+	if p.Name != "dustin" {
+		t.Errorf("Expected to set name through value, got %q", p.Name)
+	}
+
+	v, err = ReflectValue(p, "/addresses/0/street")
+	if err != nil {
+		t.Fatalf("Error reflecting street: %v", err)
+	}
+	*(v.Addr().Interface().(*string)) = "1 Infinite Loop"
+	if p.Addresses[0].Street != "1 Infinite Loop" {
+		t.Errorf("Expected to set street through address, got %q",
+			p.Addresses[0].Street)
+	}
+
+	v, err = ReflectValue(p, "/nestedmap/pi")
+	if err != nil {
+		t.Fatalf("Error reflecting map entry: %v", err)
+	}
+	if v.CanAddr() || v.Float() != 3.14 {
+		t.Errorf("Expected unaddressable 3.14, got %v (%v)", v, v.CanAddr())
+	}
+
+	v, err = ReflectValue(*p, "/aliases/0")
+	if err != nil {
+		t.Fatalf("Error reflecting alias: %v", err)
+	}
+	if !v.CanSet() {
+		t.Errorf("Expected slice elements to be settable")
+	}
+
+	v, err = ReflectValue(*p, "/name")
+	if err != nil || v.CanAddr() {
+		t.Errorf("Expected unaddressable name from a struct copy, got %v/%v",
+			v.CanAddr(), err)
+	}
+
+	for _, path := range []string{"/missing", "/aliases/3", "/aliases/-1", "/name/x"} {
+		_, err = ReflectValue(p, path)
+		pe, ok := err.(*PointerError)
+		if !ok || pe.Pointer != path || pe.Err != ErrNotFound {
+			t.Errorf("Expected not found error at %v, got %v", path, err)
+		}
+	}
+}
+
+func TestReflectListValues(t *testing.T) {
+	vals, err := ReflectListValues(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pointers, err := ReflectListPointers(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vals) != len(pointers) {
+		t.Fatalf("Expected %v values, got %v", len(pointers), len(vals))
+	}
+	for _, pv := range vals {
+		exp := Reflect(input, pv.Pointer)
+		if !reflect.DeepEqual(pv.Value.Interface(), exp) {
+			t.Errorf("At %v, expected %#v, got %#v", pv.Pointer, exp, pv.Value)
+		}
+	}
+}
+
+func TestReflectOK(t *testing.T) {
+	type nullable struct {
+		P *address
+		I interface{}
+	}
+	n := &nullable{}
+
+	for _, path := range []string{"", "/P", "/I"} {
+		if _, ok := ReflectOK(n, path); !ok {
+			t.Errorf("Expected to find %v", path)
+		}
+	}
+	if v, _ := ReflectOK(n, "/I"); v != nil {
+		t.Errorf("Expected nil at /I, got %#v", v)
+	}
+
+	for _, path := range []string{"/Q", "/P/street", "/I/0"} {
+		if v, ok := ReflectOK(n, path); ok {
+			t.Errorf("Expected not to find %v, got %#v", path, v)
+		}
+	}
+}
+
+func TestReflectListPointersWith(t *testing.T) {
+	tests := []struct {
+		opts ListOptions
+		exp  []string
+	}{
+		{ListOptions{MaxDepth: 1}, []string{"", "/name", "/Twitter",
+			"/aliases", "/addresses", "/name~0contained",
+			"/name~1contained", "/AnActualArray", "/nestedmap",
+			"/MapIntKey", "/MapUintKey", "/MapFloatKey"}},
+		{ListOptions{Prefix: "/addresses"}, []string{"/addresses",
+			"/addresses/0", "/addresses/0/street", "/addresses/0/Zip"}},
+		{ListOptions{Prefix: "/addresses", LeavesOnly: true},
+			[]string{"/addresses/0/street", "/addresses/0/Zip"}},
+		{ListOptions{ContainersOnly: true, MaxDepth: 2}, []string{"",
+			"/aliases", "/addresses", "/addresses/0", "/AnActualArray",
+			"/nestedmap", "/MapIntKey", "/MapUintKey", "/MapFloatKey"}},
+		{ListOptions{Prefix: "/missing"}, nil},
+	}
+
+	for _, test := range tests {
+		got, err := ReflectListPointersWith(input, test.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !compareStringArrayIgnoringOrder(test.exp, got) {
+			t.Errorf("With %+v, expected %#v, got %#v", test.opts, test.exp, got)
+		}
+	}
+}
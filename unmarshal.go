@@ -0,0 +1,247 @@
+package jsonpointer
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dustin/gojson"
+)
+
+// Unmarshal fills in the fields of the struct v points to from the
+// locations in data named by their jsonpointer tags, finding them all
+// in one pass:
+//
+//	type Summary struct {
+//		Name  string `jsonpointer:"/data/children/0/data/name,required"`
+//		Kind  string `jsonpointer:"/kind,default=Listing"`
+//		Owner Owner  `jsonpointer:"/data/owner"`
+//	}
+//
+// A field of struct type (or pointer to one) whose own fields are
+// tagged is filled in field by field, their pointers taken relative
+// to its tag, or to the enclosing struct if it has none.  A nil
+// pointer to such a struct is only allocated if a value other than
+// null is found for it, and it can't have a default.  Other tagged fields are decoded from
+// the value found with json.Unmarshal.  Missing values are left alone
+// unless a default is given, which is decoded as JSON or, for
+// strings, taken literally.  A missing required value is reported as
+// a PointerError wrapping ErrNotFound.  Untagged fields and those
+// tagged "-" are ignored, as is the omitempty option Marshal uses.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("jsonpointer: Unmarshal needs a non-nil struct pointer, got %T", v)
+	}
+
+	var fields []taggedField
+	if err := collectTagged(rv.Elem(), "", nil, true, &fields); err != nil {
+		return err
+	}
+	paths := make([]string, len(fields))
+	for i, f := range fields {
+		paths[i] = f.pointer
+	}
+	found, err := FindManyOrdered(data, paths)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if !f.nested {
+			if err := f.set(found[i]); err != nil {
+				return err
+			}
+			continue
+		}
+		switch {
+		case found[i] == nil && f.required:
+			return &PointerError{f.pointer, ErrNotFound}
+		case !f.alloc.IsValid():
+		case found[i] == nil || string(bytes.TrimSpace(found[i])) == "null":
+			// Leave the pointer nil, as encoding/json does for
+			// null, and its fields unset.
+			i += f.inner
+		default:
+			f.v.Set(f.alloc)
+		}
+	}
+	return nil
+}
+
+// taggedField is a settable field and where in a document to find it.
+type taggedField struct {
+	v          reflect.Value
+	pointer    string
+	required   bool
+	omitEmpty  bool
+	hasDefault bool
+	def        string
+	// nested is set for a struct filled in field by field, the
+	// next inner fields being its own.  If v is a nil pointer,
+	// alloc is the struct they're set in, for v to point to once
+	// the struct's value is found.
+	nested bool
+	inner  int
+	alloc  reflect.Value
+}
+
+func (f taggedField) set(val []byte) error {
+	if val == nil {
+		switch {
+		case f.required:
+			return &PointerError{f.pointer, ErrNotFound}
+		case !f.hasDefault:
+			return nil
+		}
+		if err := json.Unmarshal([]byte(f.def), f.v.Addr().Interface()); err != nil {
+			if f.v.Kind() != reflect.String {
+				return &PointerError{f.pointer,
+					fmt.Errorf("invalid default %q: %w", f.def, err)}
+			}
+			f.v.SetString(f.def)
+		}
+		return nil
+	}
+	if err := json.Unmarshal(val, f.v.Addr().Interface()); err != nil {
+		return &PointerError{f.pointer, err}
+	}
+	return nil
+}
+
+// parsePointerTag splits a jsonpointer tag into the pointer and its
+// options.  Everything after default= is the default.
+func parsePointerTag(tag string) (f taggedField, err error) {
+	parts := strings.Split(tag, ",")
+	f.pointer = parts[0]
+	if f.pointer != "" && f.pointer[0] != '/' {
+		return f, fmt.Errorf("invalid JSON pointer %q in tag: must be empty or begin with '/'", f.pointer)
+	}
+	for i := 1; i < len(parts); i++ {
+		switch opt := parts[i]; {
+		case opt == "required":
+			f.required = true
+		case opt == "omitempty":
+			f.omitEmpty = true
+		case strings.HasPrefix(opt, "default="):
+			f.hasDefault = true
+			f.def = strings.Join(parts[i:], ",")[len("default="):]
+			return f, nil
+		default:
+			return f, fmt.Errorf("unknown option %q in tag %q", opt, tag)
+		}
+	}
+	return f, nil
+}
+
+// collectTagged lists the tagged fields of the struct v, with
+// pointers relative to prefix.  outer holds the types of the structs
+// enclosing v.  If nested is set, each nested struct is listed before
+// its fields, and a nil pointer to one is given a struct to fill in
+// should it be found; otherwise nil pointers to nested structs are
+// skipped.
+func collectTagged(v reflect.Value, prefix string, outer []reflect.Type, nested bool,
+	fields *[]taggedField) error {
+
+	t := v.Type()
+	for _, o := range outer {
+		if o == t {
+			return fmt.Errorf("jsonpointer: recursive type %v", t)
+		}
+	}
+	outer = append(outer, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		tag, tagged := sf.Tag.Lookup("jsonpointer")
+		if tag == "-" {
+			continue
+		}
+		fv := v.Field(i)
+
+		if hasPointerTags(sf.Type, nil) {
+			f := taggedField{}
+			if tagged {
+				var err error
+				if f, err = parsePointerTag(tag); err != nil {
+					return fmt.Errorf("field %v: %w", sf.Name, err)
+				}
+				if f.hasDefault {
+					return fmt.Errorf("field %v: default not supported on struct %v",
+						sf.Name, sf.Type)
+				}
+			}
+			f.v = fv
+			f.pointer = prefix + f.pointer
+			f.nested = true
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() && !nested {
+					continue
+				}
+				if fv.IsNil() {
+					if !fv.CanSet() {
+						return fmt.Errorf("jsonpointer: cannot set embedded pointer to unexported struct %v",
+							sf.Type.Elem())
+					}
+					f.alloc = reflect.New(sf.Type.Elem())
+					fv = f.alloc
+				}
+				fv = fv.Elem()
+			}
+			at := len(*fields)
+			if nested {
+				*fields = append(*fields, f)
+			}
+			if err := collectTagged(fv, f.pointer, outer, nested, fields); err != nil {
+				return err
+			}
+			if nested {
+				(*fields)[at].inner = len(*fields) - at - 1
+			}
+			continue
+		}
+
+		if !tagged || sf.PkgPath != "" {
+			continue
+		}
+		f, err := parsePointerTag(tag)
+		if err != nil {
+			return fmt.Errorf("field %v: %w", sf.Name, err)
+		}
+		f.v = fv
+		f.pointer = prefix + f.pointer
+		*fields = append(*fields, f)
+	}
+	return nil
+}
+
+// hasPointerTags reports whether t is a struct, or pointer to one,
+// with any fields tagged jsonpointer, directly or within struct
+// fields.  seen holds the types already being checked.
+func hasPointerTags(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return false
+	}
+	if seen == nil {
+		seen = map[reflect.Type]bool{}
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if _, ok := sf.Tag.Lookup("jsonpointer"); ok {
+			return true
+		}
+		if hasPointerTags(sf.Type, seen) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package jsonpointer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const unmarshalSrc = `{"kind": "Listing", "data": {"children": [{"data": {
+	"name": "t3_1", "score": 42, "tags": ["a", "b"],
+	"author": {"name": "someone", "karma": 7}}}]}}`
+
+type unmarshalAuthor struct {
+	Name  string `jsonpointer:"/name"`
+	Karma int    `jsonpointer:"/karma"`
+}
+
+type unmarshalPost struct {
+	Name   string           `jsonpointer:"/name,required"`
+	Score  float64          `jsonpointer:"/score"`
+	Tags   []string         `jsonpointer:"/tags"`
+	Author *unmarshalAuthor `jsonpointer:"/author"`
+}
+
+type unmarshalCommon struct {
+	Kind string `jsonpointer:"/kind"`
+}
+
+type unmarshalListing struct {
+	unmarshalCommon
+	First   unmarshalPost          `jsonpointer:"/data/children/0/data"`
+	Raw     map[string]interface{} `jsonpointer:"/data/children/0/data/author"`
+	Missing string                 `jsonpointer:"/nope,default=a, b"`
+	Count   int                    `jsonpointer:"/count,default=3"`
+	Ignored string                 `jsonpointer:"-"`
+	Plain   string
+}
+
+func TestUnmarshal(t *testing.T) {
+	var got unmarshalListing
+	got.Ignored = "untouched"
+	if err := Unmarshal([]byte(unmarshalSrc), &got); err != nil {
+		t.Fatalf("Error unmarshaling: %v", err)
+	}
+	exp := unmarshalListing{
+		unmarshalCommon: unmarshalCommon{"Listing"},
+		First: unmarshalPost{"t3_1", 42, []string{"a", "b"},
+			&unmarshalAuthor{"someone", 7}},
+		Raw:     map[string]interface{}{"name": "someone", "karma": 7.0},
+		Missing: "a, b",
+		Count:   3,
+		Ignored: "untouched",
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %#v, got %#v", exp, got)
+	}
+}
+
+func TestUnmarshalRequired(t *testing.T) {
+	var got struct {
+		Post unmarshalPost `jsonpointer:"/data/children/1/data"`
+	}
+	err := Unmarshal([]byte(unmarshalSrc), &got)
+	var perr *PointerError
+	if !errors.As(err, &perr) || !errors.Is(err, ErrNotFound) ||
+		perr.Pointer != "/data/children/1/data/name" {
+		t.Errorf("Expected missing /data/children/1/data/name, got %v", err)
+	}
+}
+
+func TestUnmarshalNested(t *testing.T) {
+	type inner struct {
+		Y int `jsonpointer:"/y"`
+	}
+	var required struct {
+		Z *inner `jsonpointer:"/z,required"`
+	}
+	err := Unmarshal([]byte(`{"a": 1}`), &required)
+	var perr *PointerError
+	if !errors.As(err, &perr) || !errors.Is(err, ErrNotFound) || perr.Pointer != "/z" {
+		t.Errorf("Expected missing /z, got %v", err)
+	}
+	if required.Z != nil {
+		t.Errorf("Expected nil Z, got %#v", required.Z)
+	}
+
+	type optional struct {
+		Z *inner `jsonpointer:"/z"`
+		N *inner `jsonpointer:"/n"`
+		E *inner `jsonpointer:"/e"`
+		V inner  `jsonpointer:"/v"`
+	}
+	var got optional
+	if err := Unmarshal([]byte(`{"z": {"y": 2}, "n": null, "e": {}}`), &got); err != nil {
+		t.Fatalf("Error unmarshaling: %v", err)
+	}
+	exp := optional{Z: &inner{2}, E: &inner{}}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %#v, got %#v", exp, got)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var wrongType struct {
+		Kind int `jsonpointer:"/kind"`
+	}
+	var badDefault struct {
+		N int `jsonpointer:"/n,default=x"`
+	}
+	var badTag struct {
+		N int `jsonpointer:"n"`
+	}
+	var badOption struct {
+		N int `jsonpointer:"/n,optional"`
+	}
+	type node struct {
+		Name string `jsonpointer:"/name"`
+		Next *node  `jsonpointer:"/next"`
+	}
+	var recursive node
+	var structDefault struct {
+		Author *unmarshalAuthor `jsonpointer:"/author,default={}"`
+	}
+	tests := []interface{}{
+		&wrongType, &badDefault, &badTag, &badOption, &recursive, &structDefault,
+		wrongType, nil, new(int),
+	}
+	for _, test := range tests {
+		if err := Unmarshal([]byte(unmarshalSrc), test); err == nil {
+			t.Errorf("Expected error unmarshaling into %T", test)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package jsonpointer
+
+import (
+	"strconv"
+
+	"github.com/dustin/gojson"
+)
+
+// walker steps the scanner through a document, keeping the tokens of
+// the pointer to the current key or element.  Optionally, it checks
+// limits and cancellation and applies a duplicate key policy as it
+// goes, so everything reading a document's pointers treats them
+// alike.
+type walker struct {
+	data []byte
+	scan json.Scanner
+	// offset is just past the last byte stepped over.
+	offset       int
+	beganLiteral int
+	// tokens holds the key or index within each open container.
+	tokens []string
+
+	// prefix is the pointer to the document within an enclosing
+	// one, base its depth, and start its offset, for pointers and
+	// errors to be reported within the enclosing document.
+	prefix      string
+	base, start int
+	// lim, if set, bounds the document.
+	lim    *Limits
+	cancel canceller
+
+	// seen holds the keys of each open object when duplicates are
+	// tracked.
+	seen   []map[string]bool
+	dups   bool
+	policy DuplicatePolicy
+	// duplicate is set when the key last stepped over appeared
+	// earlier in its object, and replaced when it is to replace
+	// the earlier member under DuplicateLast.
+	duplicate, replaced bool
+	// While positive, suppress is the depth of a duplicate member
+	// being left out under DuplicateFirst.
+	suppress int
+}
+
+func newWalker(data []byte) *walker {
+	w := &walker{data: data}
+	w.scan.Reset()
+	return w
+}
+
+// trackDuplicates has the walker apply the given policy to duplicate
+// keys.
+func (w *walker) trackDuplicates(p DuplicatePolicy) {
+	w.dups = true
+	w.policy = p
+}
+
+func (w *walker) done() bool {
+	return w.offset >= len(w.data)
+}
+
+// step scans the next byte, returning the scanner's event.  Invalid
+// JSON is reported as a SyntaxError.
+func (w *walker) step() (int, error) {
+	if err := w.cancel.check(w.offset); err != nil {
+		return 0, err
+	}
+	op := w.scan.Step(&w.scan, int(w.data[w.offset]))
+	if op == json.ScanError {
+		return op, syntaxError(w.data)
+	}
+	w.offset++
+	return op, w.track(op)
+}
+
+// finish checks that the document is complete once every byte has
+// been stepped over.
+func (w *walker) finish() error {
+	if err := w.cancel.check(w.offset); err != nil {
+		return err
+	}
+	if w.scan.EOF() == json.ScanError {
+		return syntaxError(w.data)
+	}
+	return nil
+}
+
+// track follows the scanner's event for the byte just stepped over.
+func (w *walker) track(op int) error {
+	w.duplicate, w.replaced = false, false
+	depth := len(w.tokens)
+	switch op {
+	case json.ScanBeginArray, json.ScanBeginObject:
+		if w.lim != nil {
+			err := w.lim.checkDepth(w.base+depth+1, w.start+w.offset-1)
+			if err != nil {
+				return err
+			}
+		}
+		if op == json.ScanBeginArray {
+			w.tokens = append(w.tokens, "0")
+		} else {
+			w.tokens = append(w.tokens, "")
+		}
+		if w.dups {
+			w.seen = append(w.seen, nil)
+		}
+	case json.ScanObjectKey:
+		raw := w.data[w.beganLiteral-1 : w.offset-1]
+		if w.lim != nil {
+			if err := w.lim.checkKey(raw, w.start+w.beganLiteral-1); err != nil {
+				return err
+			}
+		}
+		k := grokLiteral(raw)
+		w.tokens[depth-1] = k
+		if w.dups {
+			return w.key(k, depth)
+		}
+	case json.ScanBeginLiteral:
+		w.beganLiteral = w.offset
+	case json.ScanArrayValue:
+		n := mustParseInt(w.tokens[depth-1])
+		w.tokens[depth-1] = strconv.Itoa(n + 1)
+	case json.ScanEndArray, json.ScanEndObject:
+		if w.suppress == depth {
+			w.suppress = 0
+		}
+		w.tokens = sliceToEnd(w.tokens)
+		if w.dups {
+			w.seen = w.seen[:depth-1]
+		}
+	}
+	return nil
+}
+
+// key notes the key k of a member of the object at depth, applying
+// the duplicate policy.
+func (w *walker) key(k string, depth int) error {
+	if w.suppress == depth {
+		w.suppress = 0
+	}
+	seen := w.seen[depth-1]
+	if seen == nil {
+		seen = map[string]bool{}
+		w.seen[depth-1] = seen
+	}
+	w.duplicate = seen[k]
+	seen[k] = true
+	if !w.duplicate || w.suppress != 0 {
+		return nil
+	}
+	switch w.policy {
+	case DuplicateFirst:
+		w.suppress = depth
+	case DuplicateLast:
+		w.replaced = true
+	case DuplicateError:
+		return &PointerError{w.pointer(), ErrDuplicateKey}
+	}
+	return nil
+}
+
+// suppressed reports whether the walker is within a duplicate member
+// being left out.
+func (w *walker) suppressed() bool {
+	return w.suppress != 0
+}
+
+// depth returns the number of tokens in the current pointer.
+func (w *walker) depth() int {
+	return w.base + len(w.tokens)
+}
+
+// pointer returns the current pointer.
+func (w *walker) pointer() string {
+	return w.pointerAt(w.depth())
+}
+
+// pointerAt returns the current pointer's ancestor at depth.
+func (w *walker) pointerAt(depth int) string {
+	return w.prefix + Join(w.tokens[:depth-w.base]...)
+}
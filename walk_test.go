@@ -0,0 +1,78 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dustin/gojson"
+)
+
+func TestWalker(t *testing.T) {
+	data := []byte(`{"a": [1, {"b~/": 2}], "a": {"c": 3}, "d": {"e": [], "e": 4}}`)
+	w := newWalker(data)
+	w.trackDuplicates(DuplicateFirst)
+	var keys, dups []string
+	for !w.done() {
+		op, err := w.step()
+		if err != nil {
+			t.Fatalf("Error walking: %v", err)
+		}
+		if op != json.ScanObjectKey {
+			continue
+		}
+		if w.duplicate {
+			dups = append(dups, w.pointer())
+		} else if !w.suppressed() {
+			keys = append(keys, w.pointer())
+		}
+	}
+	if err := w.finish(); err != nil {
+		t.Fatalf("Error finishing: %v", err)
+	}
+	exp := []string{"/a", "/a/1/b~0~1", "/d", "/d/e"}
+	if !reflect.DeepEqual(keys, exp) {
+		t.Errorf("Expected keys %v, got %v", exp, keys)
+	}
+	exp = []string{"/a", "/d/e"}
+	if !reflect.DeepEqual(dups, exp) {
+		t.Errorf("Expected duplicates %v, got %v", exp, dups)
+	}
+}
+
+func TestWalkerPolicy(t *testing.T) {
+	data := []byte(`{"x": {"a": 1, "a": 2}}`)
+	for _, p := range []DuplicatePolicy{DuplicateFirst, DuplicateLast, DuplicateError} {
+		w := newWalker(data)
+		w.trackDuplicates(p)
+		var err error
+		replaced, suppressed := false, false
+		for !w.done() && err == nil {
+			_, err = w.step()
+			replaced = replaced || w.replaced
+			suppressed = suppressed || w.suppressed()
+		}
+		if p == DuplicateError {
+			if perr, ok := err.(*PointerError); !ok || perr.Pointer != "/x/a" {
+				t.Errorf("Expected duplicate /x/a, got %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error walking with %v: %v", p, err)
+		}
+		if replaced != (p == DuplicateLast) || suppressed != (p == DuplicateFirst) {
+			t.Errorf("With %v, got replaced=%v, suppressed=%v", p, replaced, suppressed)
+		}
+	}
+}
+
+func TestWalkerSyntaxError(t *testing.T) {
+	w := newWalker([]byte(`{"a": [1,]}`))
+	var err error
+	for !w.done() && err == nil {
+		_, err = w.step()
+	}
+	if serr, ok := err.(*SyntaxError); !ok || serr.Pointer != "/a" {
+		t.Errorf("Expected syntax error at /a, got %#v", err)
+	}
+}